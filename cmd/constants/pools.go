@@ -1,6 +1,6 @@
 package constants
 
-// Uniswap V2 Pool Addresses
+// Uniswap V2 Pool Addresses: the built-in pools a registry file (--pools-file) is merged with
 
 var UniV2Pools = map[string]string{
 	"eEUR_eAUD_Pool": "0x47167006b08358292bc99eb1be24124e7363ba50",
@@ -32,11 +32,48 @@ var UniV2Pools = map[string]string{
     "wTEL_eEUR_Pool": "0x0b3fe394a0faeb9011bfc1f0893080645e4266fa",
 }
 
+// UniV2Router is the Uniswap V2 Router02 that swaps are sent through
+const UniV2Router = "0x7a250d5630B4cF539739dF2C5dAcb4c659F2488D"
+
+// UniV2Factory is the Uniswap V2 factory that `pools discover` reads pairs from
+const UniV2Factory = "0x5C69bEe701ef814a2B6a3EDD4B1652CB9cc5aA6f"
+
 // Target ratios for Pools (for demo purposes)
-// These would be the "ideal" ratios for each pool
+// These would be the "ideal" ratios for each pool, in whole token0 per token1
+// (reserve0/reserve1), token0 being the first symbol of the pool name
 var TargetRatios = map[string]float64{
-	"eEUR_eAUD_Pool": 1.64, // Example: 1 eEUR = 1.64 eAUD
-    "eEUR_eCAD_Pool": 1.46, // Example: 1 eEUR = 1.46 eCAD
-    "eUSD_eEUR_Pool": 0.92, // Example: 1 eUSD = 0.92 eEUR
+	"eEUR_eAUD_Pool": 0.6098, // Example: 1 eAUD = 0.6098 eEUR (1 eEUR = 1.64 eAUD)
+    "eEUR_eCAD_Pool": 0.6849, // Example: 1 eCAD = 0.6849 eEUR (1 eEUR = 1.46 eCAD)
+    "eUSD_eEUR_Pool": 1.087,  // Example: 1 eEUR = 1.087 eUSD (1 eUSD = 0.92 eEUR)
     // Add more as needed
-}
\ No newline at end of file
+}
+
+// Swap fees in basis points (30 = 0.3%)
+
+// DefaultDEX is the exchange every pool in UniV2Pools belongs to unless PoolDEX says otherwise
+const DefaultDEX = "UniswapV2"
+
+// DEXFeeBps is the swap fee charged by each supported exchange
+var DEXFeeBps = map[string]uint64{
+	"UniswapV2": 30,
+}
+
+// PoolDEX assigns pools to an exchange other than DefaultDEX
+var PoolDEX = map[string]string{}
+
+// PoolFeeBps overrides the exchange fee for individual pools
+var PoolFeeBps = map[string]uint64{}
+
+// FeeBps returns the swap fee of a pool: its own override, else its exchange's fee
+func FeeBps(poolName string) uint64 {
+	if fee, ok := PoolFeeBps[poolName]; ok {
+		return fee
+	}
+
+	dex, ok := PoolDEX[poolName]
+	if !ok {
+		dex = DefaultDEX
+	}
+
+	return DEXFeeBps[dex]
+}
@@ -0,0 +1,32 @@
+package constants
+
+// NativeSymbol is the chain's native currency, which the router trades as WETH
+const NativeSymbol = "ETH"
+
+// NativeDecimals is the number of decimals of the native currency
+const NativeDecimals = 18
+
+// WETH is the wrapped ether contract the Uniswap V2 router swaps ETH through
+const WETH = "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2"
+
+// Token addresses: the built-in ERC-20 tokens a token registry file (--tokens-file) is merged with
+var TokenAddresses = map[string]string{
+	"WETH": WETH,
+	"USDC": "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48",
+	"USDT": "0xdAC17F958D2ee523a2206206994597C13D831ec7",
+	"DAI":  "0x6B175474E89094C44Da98b954EedeAC495271d0F",
+}
+
+// TokenDecimals are the decimals of the built-in tokens
+var TokenDecimals = map[string]uint8{
+	"WETH": 18,
+	"USDC": 6,
+	"USDT": 6,
+	"DAI":  18,
+}
+
+// ResetApprovalTokens are the tokens, by checksummed address, that revert when a
+// non-zero allowance is changed to another non-zero value, so it is set to zero first
+var ResetApprovalTokens = map[string]bool{
+	"0xdAC17F958D2ee523a2206206994597C13D831ec7": true, // USDT
+}
@@ -0,0 +1,163 @@
+// Package approval checks and sets the ERC-20 allowances a spender, normally
+// the Uniswap V2 router, needs to pull the input token of a swap. Tokens such
+// as USDT refuse to change a non-zero allowance to another non-zero value, so
+// their allowance is reset to zero first.
+package approval
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/constants"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/contracts/erc20"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Mode selects how much Ensure approves
+type Mode string
+
+const (
+	Exact     Mode = "exact"     // approve exactly the swap amount
+	Unlimited Mode = "unlimited" // approve the maximum uint256 once
+	None      Mode = "none"      // never send approvals
+)
+
+// ParseMode parses an --approval flag value
+func ParseMode(value string) (Mode, error) {
+	switch mode := Mode(value); mode {
+	case Exact, Unlimited, None:
+		return mode, nil
+	}
+
+	return "", fmt.Errorf("unknown approval mode %q: use exact, unlimited or none", value)
+}
+
+// Backend is what approvals need to read allowances, send approve and wait for
+// it to be mined. Both *ethclient.Client and the simulated backend's client satisfy it.
+type Backend interface {
+	bind.ContractBackend
+	bind.DeployBackend
+}
+
+// Allowance returns how much of the token the spender may pull from the owner
+func Allowance(ctx context.Context, caller bind.ContractCaller, token, owner, spender common.Address) (*big.Int, error) {
+	contract, err := erc20.NewIERC20Caller(token, caller)
+	if err != nil {
+		return nil, err
+	}
+
+	allowance, err := contract.Allowance(&bind.CallOpts{Context: ctx}, owner, spender)
+	if err != nil {
+		return nil, fmt.Errorf("reading allowance of %s: %w", token.Hex(), err)
+	}
+
+	return allowance, nil
+}
+
+// Ensure makes sure the spender may pull amount of the token from opts.From,
+// sending approve transactions and waiting for them when the allowance is too
+// low. It returns the transactions it sent, none when the allowance suffices.
+// Mode None only checks the allowance.
+func Ensure(ctx context.Context, backend Backend, opts *bind.TransactOpts, token, spender common.Address, amount *big.Int, mode Mode) ([]*types.Transaction, error) {
+	allowance, err := Allowance(ctx, backend, token, opts.From, spender)
+	if err != nil {
+		return nil, err
+	}
+	if allowance.Cmp(amount) >= 0 {
+		return nil, nil
+	}
+	if mode == None {
+		return nil, fmt.Errorf("allowance of %s is %s, below the %s needed, and --approval is none", token.Hex(), allowance, amount)
+	}
+
+	value := amount
+	if mode == Unlimited {
+		value = math.MaxBig256
+	}
+
+	var sent []*types.Transaction
+	if allowance.Sign() > 0 && needsReset(ctx, backend, opts.From, token, spender, value) {
+		tx, err := approve(ctx, backend, opts, token, spender, new(big.Int))
+		if tx != nil {
+			sent = append(sent, tx)
+		}
+		if err != nil {
+			return sent, fmt.Errorf("resetting allowance: %w", err)
+		}
+	}
+
+	tx, err := approve(ctx, backend, opts, token, spender, value)
+	if tx != nil {
+		sent = append(sent, tx)
+	}
+
+	return sent, err
+}
+
+// Revoke sets the spender's allowance over the token to zero
+func Revoke(ctx context.Context, backend Backend, opts *bind.TransactOpts, token, spender common.Address) (*types.Transaction, error) {
+	return approve(ctx, backend, opts, token, spender, new(big.Int))
+}
+
+// approve sends approve(spender, value) and waits for it to be mined
+func approve(ctx context.Context, backend Backend, opts *bind.TransactOpts, token, spender common.Address, value *big.Int) (*types.Transaction, error) {
+	contract, err := erc20.NewIERC20Transactor(token, backend)
+	if err != nil {
+		return nil, err
+	}
+
+	txOpts := *opts
+	txOpts.Context = ctx
+	tx, err := contract.Approve(&txOpts, spender, value)
+	if err != nil {
+		return nil, fmt.Errorf("sending approve: %w", err)
+	}
+
+	receipt, err := bind.WaitMined(ctx, backend, tx)
+	if err != nil {
+		return tx, fmt.Errorf("waiting for %s: %w", tx.Hash().Hex(), err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return tx, errors.New("approve transaction reverted")
+	}
+
+	return tx, nil
+}
+
+// needsReset reports whether the token must be approved down to zero before a
+// new non-zero allowance: it is a known USDT-style token, or approving value
+// directly reverts in eth_call
+func needsReset(ctx context.Context, caller bind.ContractCaller, owner, token, spender common.Address, value *big.Int) bool {
+	if constants.ResetApprovalTokens[token.Hex()] {
+		return true
+	}
+
+	parsed, err := erc20.IERC20MetaData.GetAbi()
+	if err != nil {
+		return false
+	}
+	data, err := parsed.Pack("approve", spender, value)
+	if err != nil {
+		return false
+	}
+
+	output, err := caller.CallContract(ctx, ethereum.CallMsg{From: owner, To: &token, Data: data}, nil)
+	if err != nil {
+		return true
+	}
+
+	// Some tokens return false instead of reverting
+	results, err := parsed.Unpack("approve", output)
+	if err != nil || len(results) == 0 {
+		return false
+	}
+
+	return !*abi.ConvertType(results[0], new(bool)).(*bool)
+}
@@ -0,0 +1,74 @@
+package approval
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/testchain"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+)
+
+func TestParseMode(t *testing.T) {
+	for _, value := range []string{"exact", "unlimited", "none"} {
+		if mode, err := ParseMode(value); err != nil || string(mode) != value {
+			t.Errorf("ParseMode(%q) = %q, %v", value, mode, err)
+		}
+	}
+	if _, err := ParseMode("max"); err == nil {
+		t.Error("ParseMode accepted max")
+	}
+}
+
+func TestEnsure(t *testing.T) {
+	ctx := context.Background()
+	genesis := testchain.NewGenesis(t)
+	token := genesis.Token("eUSD", 6)
+	chain := genesis.Start(t)
+	client, opts := chain.Client(), chain.TransactOpts(t)
+	spender := common.HexToAddress("0x00000000000000000000000000000000000a11ce")
+
+	allowance := func() *big.Int {
+		t.Helper()
+		value, err := Allowance(ctx, client, token, opts.From, spender)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return value
+	}
+
+	// Mode none only checks
+	if _, err := Ensure(ctx, client, opts, token, spender, big.NewInt(100), None); err == nil {
+		t.Error("mode none passed without an allowance")
+	}
+
+	sent, err := Ensure(ctx, client, opts, token, spender, big.NewInt(100), Exact)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sent) != 1 || allowance().Int64() != 100 {
+		t.Errorf("exact approval sent %d transactions for an allowance of %s", len(sent), allowance())
+	}
+
+	// A sufficient allowance sends nothing, whatever the mode
+	for _, mode := range []Mode{Exact, Unlimited, None} {
+		if sent, err := Ensure(ctx, client, opts, token, spender, big.NewInt(60), mode); err != nil || len(sent) != 0 {
+			t.Errorf("%s with enough allowance sent %d transactions, error %v", mode, len(sent), err)
+		}
+	}
+
+	if _, err := Ensure(ctx, client, opts, token, spender, big.NewInt(200), Unlimited); err != nil {
+		t.Fatal(err)
+	}
+	if allowance().Cmp(math.MaxBig256) != 0 {
+		t.Errorf("unlimited approval left an allowance of %s", allowance())
+	}
+
+	if _, err := Revoke(ctx, client, opts, token, spender); err != nil {
+		t.Fatal(err)
+	}
+	if allowance().Sign() != 0 {
+		t.Errorf("revoke left an allowance of %s", allowance())
+	}
+}
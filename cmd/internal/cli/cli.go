@@ -0,0 +1,160 @@
+// Package cli holds the flag handling the trading commands share: loading the
+// pool and token registries, unlocking the signing account with the gas flags
+// applied, and approving the router before a swap. Flags are read by name, so
+// every command that uses a helper must define the flags it reads.
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/approval"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/config"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/password"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/poolreader"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/registry"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/signer"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/swap"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/tokens"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+)
+
+// OpenRegistry loads the pool registry: the built-in pools merged with the file
+// given by --pools-file, the environment or the --config file
+func OpenRegistry(cmd *cobra.Command) (*registry.Registry, error) {
+	poolsFile, _ := cmd.Flags().GetString("pools-file")
+	configPath, _ := cmd.Flags().GetString("config")
+
+	path, err := config.PoolsFile(poolsFile, configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return registry.Load(path)
+}
+
+// LoadTokens loads the token registry: the built-in tokens merged with the
+// file given by --tokens-file, the environment or the --config file, and the
+// tokens of the pools
+func LoadTokens(cmd *cobra.Command, pools *registry.Registry) (*tokens.Registry, error) {
+	tokensFile, _ := cmd.Flags().GetString("tokens-file")
+	configPath, _ := cmd.Flags().GetString("config")
+
+	path, err := config.TokensFile(tokensFile, configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return tokens.Load(path, pools)
+}
+
+// OpenTokens returns the token resolver over LoadTokens. Tokens the registries
+// do not describe are read through the pool reader's client.
+func OpenTokens(cmd *cobra.Command, pools *registry.Registry, reader poolreader.PoolReader) (*tokens.Resolver, error) {
+	registered, err := LoadTokens(cmd, pools)
+	if err != nil {
+		return nil, err
+	}
+
+	return tokens.NewResolver(tokens.Options{Tokens: registered, Pools: pools, Reader: reader}), nil
+}
+
+// OpenSigner unlocks the --keystore-file account, connects to the external
+// signer for --wallet, or unlocks the --wallet account found in the resolved
+// keystore directory
+func OpenSigner(cmd *cobra.Command) (signer.Signer, error) {
+	keystoreFile, _ := cmd.Flags().GetString("keystore-file")
+	wallet, _ := cmd.Flags().GetString("wallet")
+	passwordFile, _ := cmd.Flags().GetString("password-file")
+
+	signerURL, _ := cmd.Flags().GetString("signer-url")
+	configPath, _ := cmd.Flags().GetString("config")
+
+	// Without a keystore file, use the configured external signer or else look
+	// for --wallet in the keystore directory, which is only checked when needed
+	var keystoreDir string
+	if keystoreFile == "" {
+		var err error
+		if signerURL, err = config.SignerURL(signerURL, configPath); err != nil {
+			return nil, err
+		}
+		if signerURL == "" {
+			flag, _ := cmd.Flags().GetString("keystore-dir")
+			if keystoreDir, err = config.KeystoreDir(flag, configPath); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return signer.Open(signer.Options{
+		KeystoreFile: keystoreFile,
+		KeystoreDir:  keystoreDir,
+		SignerURL:    signerURL,
+		Wallet:       wallet,
+		Password:     password.Options{File: passwordFile},
+	})
+}
+
+// Transactor opens the signer and returns options signing for the client's
+// chain, with the gas flags applied
+func Transactor(cmd *cobra.Command, client signer.ChainIDReader) (*bind.TransactOpts, error) {
+	account, err := OpenSigner(cmd)
+	if err != nil {
+		return nil, fmt.Errorf("unlocking wallet: %w", err)
+	}
+
+	opts, err := signer.Transactor(cmd.Context(), account, client)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ApplyGasFlags(cmd, opts); err != nil {
+		return nil, err
+	}
+
+	return opts, nil
+}
+
+// ApplyGasFlags sets the gas price and limit from --gas-price and --gas-limit.
+// An "auto" gas price, or a command without the flags, is left for the backend to suggest.
+func ApplyGasFlags(cmd *cobra.Command, opts *bind.TransactOpts) error {
+	gasPrice, _ := cmd.Flags().GetString("gas-price")
+	gasLimit, _ := cmd.Flags().GetUint64("gas-limit")
+
+	opts.GasLimit = gasLimit
+	if gasPrice == "" || strings.EqualFold(gasPrice, "auto") {
+		return nil
+	}
+
+	wei, err := tokens.ParseUnits(gasPrice, 9)
+	if err != nil {
+		return fmt.Errorf("invalid --gas-price %q: %w", gasPrice, err)
+	}
+	opts.GasPrice = wei
+
+	return nil
+}
+
+// ApprovalMode returns the --approval mode
+func ApprovalMode(cmd *cobra.Command) (approval.Mode, error) {
+	flag, _ := cmd.Flags().GetString("approval")
+	return approval.ParseMode(flag)
+}
+
+// EnsureApproval lets the router pull the quote's input from the signing
+// account, approving it as selected by mode when the allowance is too low
+func EnsureApproval(ctx context.Context, backend approval.Backend, opts *bind.TransactOpts, router common.Address, quote *swap.Quote, mode approval.Mode) error {
+	tokenIn := quote.Route.Hops[0].From
+	sent, err := approval.Ensure(ctx, backend, opts, tokenIn.Address, router, quote.AmountIn(), mode)
+	for _, tx := range sent {
+		fmt.Printf("  Approve %s: %s\n", tokenIn.Symbol, tx.Hash().Hex())
+	}
+	if err != nil {
+		return fmt.Errorf("approving %s: %w", tokenIn.Symbol, err)
+	}
+
+	return nil
+}
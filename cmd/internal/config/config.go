@@ -0,0 +1,100 @@
+// Package config loads the optional tradebot configuration file. Settings that
+// can also be given as a flag or an environment variable are resolved here in
+// one order for every command: flag, then environment, then file, then default.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvFile is the environment variable naming the config file when --config is not given
+const EnvFile = "TRADEBOT_CONFIG"
+
+// Config is the content of the YAML config file. Every setting is optional.
+type Config struct {
+	KeystoreDir string `yaml:"keystore_dir"` // directory holding the keystore files
+	SignerURL   string `yaml:"signer_url"`   // external signer endpoint used instead of the keystore
+	PoolsFile   string `yaml:"pools_file"`   // pool registry merged with the built-in pools
+	TokensFile  string `yaml:"tokens_file"`  // token registry merged with the built-in tokens
+
+	path string // file the config was read from, empty when none was found
+}
+
+// DefaultPath returns the config file used when neither --config nor EnvFile is set:
+// tradebot/config.yaml under the user's config directory
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "tradebot", "config.yaml"), nil
+}
+
+// Load reads the config file at path, or at EnvFile or DefaultPath when path is empty.
+// A missing default file gives an empty config; a missing file that was asked for is an error.
+func Load(path string) (*Config, error) {
+	explicit := true
+	if path == "" {
+		path = os.Getenv(EnvFile)
+	}
+	if path == "" {
+		explicit = false
+
+		var err error
+		if path, err = DefaultPath(); err != nil {
+			// Without a home directory there is no default file to read
+			return &Config{}, nil
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) && !explicit {
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading config: %w", err)
+	}
+
+	// Unknown keys are rejected so a misspelled setting is not silently ignored
+	config := &Config{path: path}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(config); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parsing config %s: %w", path, err)
+	}
+
+	return config, nil
+}
+
+// Path returns the file the config was read from, or "" when none was found
+func (c *Config) Path() string {
+	return c.path
+}
+
+// resolvePath expands a leading ~ and makes a relative path from the config file
+// relative to the directory of that file, so it does not depend on the working directory
+func (c *Config) resolvePath(path string) (string, error) {
+	if rest, ok := strings.CutPrefix(path, "~"); ok && (rest == "" || rest[0] == '/') {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		path = home + rest
+	}
+
+	if !filepath.IsAbs(path) && c.path != "" {
+		path = filepath.Join(filepath.Dir(c.path), path)
+	}
+
+	return path, nil
+}
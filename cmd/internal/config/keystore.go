@@ -0,0 +1,72 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"runtime"
+)
+
+// EnvKeystoreDir is the environment variable read when --keystore-dir is not given
+const EnvKeystoreDir = "TRADEBOT_KEYSTORE_DIR"
+
+// DefaultKeystoreDir is used when the keystore directory is not set anywhere
+const DefaultKeystoreDir = "./keystore"
+
+// KeystoreDir resolves the keystore directory from the --keystore-dir flag value,
+// then EnvKeystoreDir, then keystore_dir in the config file at configPath, then
+// DefaultKeystoreDir, and checks the directory is private to its owner
+func KeystoreDir(flag, configPath string) (string, error) {
+	dir := flag
+	if dir == "" {
+		dir = os.Getenv(EnvKeystoreDir)
+	}
+	if dir == "" {
+		config, err := Load(configPath)
+		if err != nil {
+			return "", err
+		}
+		if config.KeystoreDir != "" {
+			if dir, err = config.resolvePath(config.KeystoreDir); err != nil {
+				return "", err
+			}
+		}
+	}
+	if dir == "" {
+		dir = DefaultKeystoreDir
+	}
+
+	if err := CheckKeystoreDir(dir); err != nil {
+		return "", err
+	}
+
+	return dir, nil
+}
+
+// CheckKeystoreDir verifies a keystore directory cannot be read or entered by other
+// users. A directory that does not exist yet passes: it is created with mode 0700.
+func CheckKeystoreDir(dir string) error {
+	info, err := os.Stat(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if !info.IsDir() {
+		return fmt.Errorf("keystore directory %s is not a directory", dir)
+	}
+
+	// Windows does not report group and other permission bits
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+
+	if mode := info.Mode().Perm(); mode&0o077 != 0 {
+		return fmt.Errorf("keystore directory %s is accessible by other users (mode %04o): run chmod 700 %s", dir, mode, dir)
+	}
+
+	return nil
+}
@@ -0,0 +1,28 @@
+package config
+
+import "os"
+
+// EnvPoolsFile is the environment variable read when --pools-file is not given
+const EnvPoolsFile = "TRADEBOT_POOLS_FILE"
+
+// PoolsFile resolves the pool registry file from the --pools-file flag value, then
+// EnvPoolsFile, then pools_file in the config file at configPath. An empty result
+// means only the built-in pools are used.
+func PoolsFile(flag, configPath string) (string, error) {
+	if flag != "" {
+		return flag, nil
+	}
+	if path := os.Getenv(EnvPoolsFile); path != "" {
+		return path, nil
+	}
+
+	config, err := Load(configPath)
+	if err != nil {
+		return "", err
+	}
+	if config.PoolsFile == "" {
+		return "", nil
+	}
+
+	return config.resolvePath(config.PoolsFile)
+}
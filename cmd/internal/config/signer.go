@@ -0,0 +1,25 @@
+package config
+
+import "os"
+
+// EnvSignerURL is the environment variable read when --signer-url is not given
+const EnvSignerURL = "TRADEBOT_SIGNER_URL"
+
+// SignerURL resolves the external signer endpoint from the --signer-url flag value,
+// then EnvSignerURL, then signer_url in the config file at configPath. An empty
+// result means transactions are signed from the keystore.
+func SignerURL(flag, configPath string) (string, error) {
+	if flag != "" {
+		return flag, nil
+	}
+	if url := os.Getenv(EnvSignerURL); url != "" {
+		return url, nil
+	}
+
+	config, err := Load(configPath)
+	if err != nil {
+		return "", err
+	}
+
+	return config.SignerURL, nil
+}
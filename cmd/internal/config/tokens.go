@@ -0,0 +1,28 @@
+package config
+
+import "os"
+
+// EnvTokensFile is the environment variable read when --tokens-file is not given
+const EnvTokensFile = "TRADEBOT_TOKENS_FILE"
+
+// TokensFile resolves the token registry file from the --tokens-file flag value,
+// then EnvTokensFile, then tokens_file in the config file at configPath. An
+// empty result means only the built-in tokens and pool tokens are known.
+func TokensFile(flag, configPath string) (string, error) {
+	if flag != "" {
+		return flag, nil
+	}
+	if path := os.Getenv(EnvTokensFile); path != "" {
+		return path, nil
+	}
+
+	config, err := Load(configPath)
+	if err != nil {
+		return "", err
+	}
+	if config.TokensFile == "" {
+		return "", nil
+	}
+
+	return config.resolvePath(config.TokensFile)
+}
@@ -0,0 +1,5 @@
+// Package erc20 holds the abigen binding for the standard ERC-20 token
+// interface. Regenerate with `go generate ./...` after editing abi/IERC20.abi.
+package erc20
+
+//go:generate abigen --abi abi/IERC20.abi --pkg erc20 --type IERC20 --out erc20.go
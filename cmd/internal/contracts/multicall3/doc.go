@@ -0,0 +1,11 @@
+// Package multicall3 holds the abigen binding for Multicall3, which batches
+// many read calls into a single eth_call. Regenerate with `go generate ./...`
+// after editing abi/Multicall3.abi.
+package multicall3
+
+import "github.com/ethereum/go-ethereum/common"
+
+//go:generate abigen --abi abi/Multicall3.abi --pkg multicall3 --type Multicall3 --out multicall3.go
+
+// DefaultAddress is where Multicall3 is deployed on mainnet and most EVM networks
+var DefaultAddress = common.HexToAddress("0xcA11bde05977b3631167028862bE2a173976CA11")
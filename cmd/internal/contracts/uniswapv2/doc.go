@@ -0,0 +1,8 @@
+// Package uniswapv2 holds the abigen bindings for the Uniswap V2 contracts the
+// bot talks to. The ABIs live in the abi/ directory; regenerate with
+// `go generate ./...` after editing them.
+package uniswapv2
+
+//go:generate abigen --abi abi/IUniswapV2Factory.abi --pkg uniswapv2 --type IUniswapV2Factory --out factory.go
+//go:generate abigen --abi abi/IUniswapV2Pair.abi --pkg uniswapv2 --type IUniswapV2Pair --out pair.go
+//go:generate abigen --abi abi/IUniswapV2Router02.abi --pkg uniswapv2 --type IUniswapV2Router02 --out router.go
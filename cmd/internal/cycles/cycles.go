@@ -0,0 +1,213 @@
+// Package cycles finds arbitrage cycles across a set of Uniswap V2 pools.
+//
+// Every pool contributes two directed edges between its tokens, weighted by
+// -log of the spot exchange rate after fees. A cycle whose weights sum to less
+// than zero returns more than it started with, so profitable cycles are
+// exactly the negative cycles of the graph. Bellman-Ford tells whether any
+// exist; a bounded depth-first search then enumerates them up to a maximum
+// number of hops and sizes each one exactly with the swap math.
+package cycles
+
+import (
+	"math"
+	"math/big"
+	"sort"
+
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/poolreader"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/swapmath"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Edge is one trading direction through a pool
+type Edge struct {
+	PoolName   string
+	Pool       common.Address
+	From       poolreader.Token
+	To         poolreader.Token
+	ReserveIn  *big.Int
+	ReserveOut *big.Int
+	FeeBps     uint64
+	Weight     float64 // -log(spot rate after fee)
+}
+
+// Cycle is a sequence of edges that starts and ends at the same token
+type Cycle struct {
+	Edges         []Edge
+	AmountIn      *big.Int   // input that maximises profit, in the start token's base units
+	Amounts       []*big.Int // amount held after each hop, starting with AmountIn
+	Profit        *big.Int   // final amount minus AmountIn
+	ProfitPercent float64
+}
+
+// Start returns the token the cycle starts and ends with
+func (c *Cycle) Start() poolreader.Token {
+	return c.Edges[0].From
+}
+
+// Graph is the token graph built from a set of pools
+type Graph struct {
+	tokens map[common.Address]poolreader.Token
+	edges  map[common.Address][]Edge
+}
+
+// NewGraph creates an empty token graph
+func NewGraph() *Graph {
+	return &Graph{
+		tokens: make(map[common.Address]poolreader.Token),
+		edges:  make(map[common.Address][]Edge),
+	}
+}
+
+// AddPool adds both trading directions of a pool. Empty pools are ignored.
+func (g *Graph) AddPool(poolName string, reserves *poolreader.Reserves, token0, token1 poolreader.Token, feeBps uint64) {
+	if reserves.Reserve0.Sign() <= 0 || reserves.Reserve1.Sign() <= 0 || feeBps >= swapmath.BpsDenominator {
+		return
+	}
+
+	g.tokens[token0.Address] = token0
+	g.tokens[token1.Address] = token1
+
+	g.addEdge(poolName, reserves.Pool, token0, token1, reserves.Reserve0, reserves.Reserve1, feeBps)
+	g.addEdge(poolName, reserves.Pool, token1, token0, reserves.Reserve1, reserves.Reserve0, feeBps)
+}
+
+// addEdge adds one direction of a pool with its -log(rate) weight
+func (g *Graph) addEdge(poolName string, pool common.Address, from, to poolreader.Token, reserveIn, reserveOut *big.Int, feeBps uint64) {
+	rate := new(big.Float).Quo(new(big.Float).SetInt(reserveOut), new(big.Float).SetInt(reserveIn))
+	spot, _ := rate.Float64()
+	spot *= float64(swapmath.BpsDenominator-feeBps) / swapmath.BpsDenominator
+
+	g.edges[from.Address] = append(g.edges[from.Address], Edge{
+		PoolName:   poolName,
+		Pool:       pool,
+		From:       from,
+		To:         to,
+		ReserveIn:  reserveIn,
+		ReserveOut: reserveOut,
+		FeeBps:     feeBps,
+		Weight:     -math.Log(spot),
+	})
+}
+
+// HasNegativeCycle runs Bellman-Ford from a virtual source connected to every
+// token and reports whether any profitable cycle exists, of any length
+func (g *Graph) HasNegativeCycle() bool {
+	dist := make(map[common.Address]float64, len(g.tokens))
+	for token := range g.tokens {
+		dist[token] = 0
+	}
+
+	for i := 0; i < len(g.tokens); i++ {
+		relaxed := false
+		for from, edges := range g.edges {
+			for _, edge := range edges {
+				if d := dist[from] + edge.Weight; d < dist[edge.To.Address]-1e-12 {
+					dist[edge.To.Address] = d
+					relaxed = true
+				}
+			}
+		}
+		if !relaxed {
+			return false
+		}
+	}
+
+	// Still relaxing after |V| rounds means a negative cycle
+	return true
+}
+
+// Profitable enumerates the simple cycles of at most maxHops edges that return
+// more than they start with after fees, sized for maximum profit and sorted by
+// profit percentage. Each cycle is reported once, starting from its
+// lowest-address token.
+func (g *Graph) Profitable(maxHops int) []*Cycle {
+	if maxHops < 2 || !g.HasNegativeCycle() {
+		return nil
+	}
+
+	starts := make([]common.Address, 0, len(g.tokens))
+	for token := range g.tokens {
+		starts = append(starts, token)
+	}
+	sort.Slice(starts, func(i, j int) bool { return starts[i].Cmp(starts[j]) < 0 })
+
+	var found []*Cycle
+	for _, start := range starts {
+		var path []Edge
+		visited := map[common.Address]bool{start: true}
+
+		var walk func(at common.Address, weight float64)
+		walk = func(at common.Address, weight float64) {
+			for _, edge := range g.edges[at] {
+				next := edge.To.Address
+
+				// Only start from the lowest token of a cycle so it is found once
+				if next.Cmp(start) < 0 {
+					continue
+				}
+
+				// Do not go straight back through the pool just used
+				if len(path) > 0 && path[len(path)-1].Pool == edge.Pool {
+					continue
+				}
+
+				if next == start {
+					if len(path) >= 1 && weight+edge.Weight < 0 {
+						if cycle := size(append(append([]Edge(nil), path...), edge)); cycle != nil {
+							found = append(found, cycle)
+						}
+					}
+					continue
+				}
+
+				if visited[next] || len(path)+1 >= maxHops {
+					continue
+				}
+
+				visited[next] = true
+				path = append(path, edge)
+				walk(next, weight+edge.Weight)
+				path = path[:len(path)-1]
+				visited[next] = false
+			}
+		}
+		walk(start, 0)
+	}
+
+	sort.Slice(found, func(i, j int) bool { return found[i].ProfitPercent > found[j].ProfitPercent })
+	return found
+}
+
+// size finds the input that maximises the cycle's profit and simulates it exactly
+func size(edges []Edge) *Cycle {
+	hops := make([]swapmath.Hop, len(edges))
+	for i, edge := range edges {
+		hops[i] = swapmath.Hop{ReserveIn: edge.ReserveIn, ReserveOut: edge.ReserveOut, FeeBps: edge.FeeBps}
+	}
+
+	amountIn := swapmath.OptimalInput(hops)
+	if amountIn == nil {
+		return nil
+	}
+
+	amounts, err := swapmath.GetAmountsOut(amountIn, hops)
+	if err != nil {
+		return nil
+	}
+
+	profit := new(big.Int).Sub(amounts[len(amounts)-1], amountIn)
+	if profit.Sign() <= 0 {
+		return nil
+	}
+
+	percent := new(big.Float).Quo(new(big.Float).SetInt(profit), new(big.Float).SetInt(amountIn))
+	profitPercent, _ := percent.Float64()
+
+	return &Cycle{
+		Edges:         edges,
+		AmountIn:      amountIn,
+		Amounts:       amounts,
+		Profit:        profit,
+		ProfitPercent: profitPercent * 100,
+	}
+}
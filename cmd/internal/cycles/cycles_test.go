@@ -0,0 +1,158 @@
+package cycles
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/poolreader"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/swapmath"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// testTokens are four 18-decimal tokens with ascending addresses
+var testTokens = []poolreader.Token{
+	{Address: common.HexToAddress("0x01"), Symbol: "eUSD", Decimals: 18},
+	{Address: common.HexToAddress("0x02"), Symbol: "eEUR", Decimals: 18},
+	{Address: common.HexToAddress("0x03"), Symbol: "eGBP", Decimals: 18},
+	{Address: common.HexToAddress("0x04"), Symbol: "eJPY", Decimals: 18},
+}
+
+// testPool is a pool between two of testTokens with whole-token reserves
+type testPool struct {
+	token0, token1     int
+	reserve0, reserve1 int64
+}
+
+// units returns amount whole 18-decimal tokens in base units
+func units(amount int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(amount), big.NewInt(1_000_000_000_000_000_000))
+}
+
+// graph builds a graph of pools with the standard 30 bps fee
+func graph(pools ...testPool) *Graph {
+	g := NewGraph()
+	for i, pool := range pools {
+		address := common.BigToAddress(big.NewInt(int64(0x100 + i)))
+		reserves := &poolreader.Reserves{Pool: address, Reserve0: units(pool.reserve0), Reserve1: units(pool.reserve1)}
+		name := testTokens[pool.token0].Symbol + "_" + testTokens[pool.token1].Symbol + "_Pool"
+		g.AddPool(name, reserves, testTokens[pool.token0], testTokens[pool.token1], 30)
+	}
+	return g
+}
+
+func TestHasNegativeCycle(t *testing.T) {
+	tests := []struct {
+		name  string
+		pools []testPool
+		want  bool
+	}{
+		{"balanced triangle", []testPool{{0, 1, 10_000, 10_000}, {1, 2, 10_000, 10_000}, {2, 0, 10_000, 10_000}}, false},
+		{"skewed triangle", []testPool{{0, 1, 10_000, 10_000}, {1, 2, 10_000, 10_000}, {2, 0, 10_000, 12_000}}, true},
+		{"skew inside the fees", []testPool{{0, 1, 10_000, 10_000}, {1, 2, 10_000, 10_000}, {2, 0, 10_000, 10_050}}, false},
+		{"two pools of one pair", []testPool{{0, 1, 10_000, 10_000}, {0, 1, 10_000, 12_000}}, true},
+		{"no cycle", []testPool{{0, 1, 10_000, 20_000}, {1, 2, 10_000, 5_000}}, false},
+	}
+	for _, test := range tests {
+		if got := graph(test.pools...).HasNegativeCycle(); got != test.want {
+			t.Errorf("%s: HasNegativeCycle = %t, want %t", test.name, got, test.want)
+		}
+	}
+}
+
+func TestProfitable(t *testing.T) {
+	skewed := []testPool{{0, 1, 10_000, 10_000}, {1, 2, 10_000, 10_000}, {2, 0, 10_000, 12_000}}
+
+	found := graph(skewed...).Profitable(3)
+	if len(found) != 1 {
+		t.Fatalf("found %d cycles, want 1", len(found))
+	}
+	cycle := found[0]
+
+	// The cycle is reported once, from its lowest token, and sells eGBP where
+	// it buys 1.2 eUSD
+	if cycle.Start() != testTokens[0] || len(cycle.Edges) != 3 {
+		t.Errorf("cycle starts at %s with %d edges", cycle.Start().Symbol, len(cycle.Edges))
+	}
+	if want := []string{"eEUR", "eGBP", "eUSD"}; cycle.Edges[0].To.Symbol != want[0] || cycle.Edges[1].To.Symbol != want[1] || cycle.Edges[2].To.Symbol != want[2] {
+		t.Errorf("cycle goes eUSD > %s > %s > %s, want eUSD > eEUR > eGBP > eUSD",
+			cycle.Edges[0].To.Symbol, cycle.Edges[1].To.Symbol, cycle.Edges[2].To.Symbol)
+	}
+
+	// The amounts are the exact swap outputs and the profit is the best one
+	hops := make([]swapmath.Hop, len(cycle.Edges))
+	for i, edge := range cycle.Edges {
+		hops[i] = swapmath.Hop{ReserveIn: edge.ReserveIn, ReserveOut: edge.ReserveOut, FeeBps: edge.FeeBps}
+	}
+	profit := func(amountIn *big.Int) *big.Int {
+		amounts, err := swapmath.GetAmountsOut(amountIn, hops)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return amounts[len(amounts)-1].Sub(amounts[len(amounts)-1], amountIn)
+	}
+	if got := profit(cycle.AmountIn); cycle.Profit.Sign() <= 0 || got.Cmp(cycle.Profit) != 0 {
+		t.Errorf("profit = %s, want %s and positive", cycle.Profit, got)
+	}
+	if len(cycle.Amounts) != 4 || cycle.Amounts[0].Cmp(cycle.AmountIn) != 0 {
+		t.Errorf("amounts = %v for input %s", cycle.Amounts, cycle.AmountIn)
+	}
+	for _, other := range []*big.Int{
+		new(big.Int).Quo(cycle.AmountIn, big.NewInt(2)),
+		new(big.Int).Mul(cycle.AmountIn, big.NewInt(2)),
+		new(big.Int).Sub(cycle.AmountIn, units(1)),
+		new(big.Int).Add(cycle.AmountIn, units(1)),
+	} {
+		if profit(other).Cmp(cycle.Profit) > 0 {
+			t.Errorf("input %s earns more than the sized input %s", other, cycle.AmountIn)
+		}
+	}
+
+	// A two-hop limit cannot close the triangle
+	if found := graph(skewed...).Profitable(2); len(found) != 0 {
+		t.Errorf("found %d cycles within two hops, want 0", len(found))
+	}
+}
+
+func TestProfitableEnumeratesCycles(t *testing.T) {
+	// A square eUSD-eEUR-eGBP-eJPY with a skewed eJPY pool and a balanced
+	// diagonal: the four-hop square and the eUSD-eGBP-eJPY triangle both pay
+	pools := []testPool{
+		{0, 1, 10_000, 10_000},
+		{1, 2, 10_000, 10_000},
+		{2, 3, 10_000, 10_000},
+		{3, 0, 10_000, 12_000},
+		{0, 2, 10_000, 10_000},
+	}
+	g := graph(pools...)
+
+	if found := g.Profitable(3); len(found) != 1 || len(found[0].Edges) != 3 {
+		t.Errorf("within three hops found %d cycles, want the triangle", len(found))
+	}
+
+	found := g.Profitable(4)
+	if len(found) != 2 {
+		t.Fatalf("within four hops found %d cycles, want 2", len(found))
+	}
+	for i, cycle := range found {
+		if cycle.Start() != testTokens[0] {
+			t.Errorf("cycle %d starts at %s, want eUSD", i, cycle.Start().Symbol)
+		}
+		if i > 0 && cycle.ProfitPercent > found[i-1].ProfitPercent {
+			t.Errorf("cycles are not sorted by profit: %v before %v", found[i-1].ProfitPercent, cycle.ProfitPercent)
+		}
+	}
+
+	// A balanced graph has nothing to enumerate
+	if found := graph(pools[:3]...).Profitable(4); len(found) != 0 {
+		t.Errorf("balanced graph gave %d cycles", len(found))
+	}
+}
+
+func TestAddPoolSkipsEmptyPools(t *testing.T) {
+	g := NewGraph()
+	empty := &poolreader.Reserves{Pool: common.HexToAddress("0x100"), Reserve0: big.NewInt(0), Reserve1: units(1)}
+	g.AddPool("empty", empty, testTokens[0], testTokens[1], 30)
+	if len(g.tokens) != 0 || len(g.edges) != 0 {
+		t.Errorf("empty pool added %d tokens and %d edge lists", len(g.tokens), len(g.edges))
+	}
+}
@@ -0,0 +1,201 @@
+// Package discovery finds Uniswap V2 pairs through their factory: the pairs
+// already deployed by index with allPairs, and new ones as their PairCreated
+// events arrive. Each pair's tokens and reserves are read so it can be filtered
+// and written to a pool registry file.
+package discovery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/contracts/uniswapv2"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/poolreader"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/registry"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Backend is the subset of an Ethereum client discovery needs. *ethclient.Client satisfies it.
+type Backend interface {
+	bind.ContractBackend
+	BlockNumber(ctx context.Context) (uint64, error)
+}
+
+// Pair is a factory pair with its tokens and current reserves
+type Pair struct {
+	Address  common.Address
+	Token0   poolreader.Token
+	Token1   poolreader.Token
+	Reserves *poolreader.Reserves
+}
+
+// Filter selects the pairs worth registering. The zero Filter accepts every pair.
+type Filter struct {
+	// Tokens are the symbols or addresses allowed; when set, both tokens of a pair must be listed
+	Tokens []string
+
+	// MinLiquidity is the minimum reserve of each token, in whole tokens
+	MinLiquidity float64
+}
+
+// Factory reads the pairs of a Uniswap V2 factory
+type Factory struct {
+	address  common.Address
+	backend  Backend
+	contract *uniswapv2.IUniswapV2Factory
+	reader   *poolreader.Live
+}
+
+// NewFactory binds the factory at address
+func NewFactory(address common.Address, backend Backend) (*Factory, error) {
+	contract, err := uniswapv2.NewIUniswapV2Factory(address, backend)
+	if err != nil {
+		return nil, fmt.Errorf("binding factory contract: %w", err)
+	}
+
+	return &Factory{
+		address:  address,
+		backend:  backend,
+		contract: contract,
+		reader:   poolreader.NewLive(backend),
+	}, nil
+}
+
+// Address returns the factory's address
+func (f *Factory) Address() common.Address {
+	return f.address
+}
+
+// PairCount calls allPairsLength() on the factory
+func (f *Factory) PairCount(ctx context.Context) (uint64, error) {
+	count, err := f.contract.AllPairsLength(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return 0, fmt.Errorf("calling allPairsLength: %w", err)
+	}
+	if !count.IsUint64() {
+		return 0, fmt.Errorf("allPairsLength %s is out of range", count)
+	}
+
+	return count.Uint64(), nil
+}
+
+// PairAt calls allPairs(index) on the factory
+func (f *Factory) PairAt(ctx context.Context, index uint64) (common.Address, error) {
+	pair, err := f.contract.AllPairs(&bind.CallOpts{Context: ctx}, new(big.Int).SetUint64(index))
+	if err != nil {
+		return common.Address{}, fmt.Errorf("calling allPairs(%d): %w", index, err)
+	}
+
+	return pair, nil
+}
+
+// Inspect reads a pair's tokens, with their symbols and decimals, and its reserves
+func (f *Factory) Inspect(ctx context.Context, address common.Address) (*Pair, error) {
+	token0, token1, err := f.reader.Tokens(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	reserves, err := f.reader.Reserves(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Pair{Address: address, Token0: token0, Token1: token1, Reserves: reserves}, nil
+}
+
+// Check returns why the filter rejects the pair, or nil when it is accepted
+func (filter Filter) Check(pair *Pair) error {
+	if len(filter.Tokens) > 0 {
+		for _, token := range []poolreader.Token{pair.Token0, pair.Token1} {
+			if !filter.allows(token) {
+				return fmt.Errorf("%s (%s) is not an allowed token", token.Symbol, token.Address.Hex())
+			}
+		}
+	}
+
+	if filter.MinLiquidity > 0 {
+		reserve0 := WholeTokens(pair.Reserves.Reserve0, pair.Token0.Decimals)
+		reserve1 := WholeTokens(pair.Reserves.Reserve1, pair.Token1.Decimals)
+		if reserve0 < filter.MinLiquidity || reserve1 < filter.MinLiquidity {
+			return fmt.Errorf("reserves of %g %s and %g %s are below %g",
+				reserve0, pair.Token0.Symbol, reserve1, pair.Token1.Symbol, filter.MinLiquidity)
+		}
+	}
+
+	return nil
+}
+
+// allows reports whether the token is in the allowlist, by address or symbol
+func (filter Filter) allows(token poolreader.Token) bool {
+	for _, allowed := range filter.Tokens {
+		if common.IsHexAddress(allowed) {
+			if common.HexToAddress(allowed) == token.Address {
+				return true
+			}
+		} else if strings.EqualFold(allowed, token.Symbol) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WholeTokens converts a raw token amount to whole tokens
+func WholeTokens(amount *big.Int, decimals uint8) float64 {
+	scale := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil))
+	whole, _ := new(big.Float).Quo(new(big.Float).SetInt(amount), scale).Float64()
+
+	return whole
+}
+
+// unsafeSymbol matches the characters not kept from a token symbol in a pool name
+var unsafeSymbol = regexp.MustCompile(`[^A-Za-z0-9.]+`)
+
+// Pool turns a pair into a registry pool of the given DEX named like the
+// built-in ones ("WETH_USDC_Pool"). When that name is taken in pools, the start
+// of the pair's address is appended so the name stays unique.
+func Pool(pair *Pair, dex string, feeBps uint64, pools *registry.Registry, source string) (*registry.Pool, error) {
+	symbol0 := strings.TrimSpace(pair.Token0.Symbol)
+	symbol1 := strings.TrimSpace(pair.Token1.Symbol)
+	if symbol0 == "" || symbol1 == "" {
+		return nil, errors.New("a token has an empty symbol")
+	}
+	if strings.EqualFold(symbol0, symbol1) {
+		return nil, fmt.Errorf("both tokens are called %s", symbol0)
+	}
+
+	for _, token := range []poolreader.Token{pair.Token0, pair.Token1} {
+		if token.Decimals > 36 {
+			return nil, fmt.Errorf("%s has %d decimals", token.Symbol, token.Decimals)
+		}
+	}
+
+	name0 := unsafeSymbol.ReplaceAllString(symbol0, "")
+	name1 := unsafeSymbol.ReplaceAllString(symbol1, "")
+	if name0 == "" || name1 == "" {
+		return nil, fmt.Errorf("symbols %q and %q cannot be used in a pool name", symbol0, symbol1)
+	}
+
+	name := fmt.Sprintf("%s_%s_Pool", name0, name1)
+	if _, taken := pools.Pool(name); taken {
+		name = fmt.Sprintf("%s_%s", name, pair.Address.Hex()[2:8])
+		if _, taken := pools.Pool(name); taken {
+			return nil, fmt.Errorf("pool name %s is taken", name)
+		}
+	}
+
+	return &registry.Pool{
+		Name:    name,
+		Address: pair.Address,
+		DEX:     dex,
+		FeeBps:  feeBps,
+		Token0:  registry.Token{Symbol: symbol0, Address: pair.Token0.Address, Decimals: pair.Token0.Decimals},
+		Token1:  registry.Token{Symbol: symbol1, Address: pair.Token1.Address, Decimals: pair.Token1.Decimals},
+		Source:  source,
+	}, nil
+}
@@ -0,0 +1,138 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/contracts/uniswapv2"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Created is a pair announced by the factory's PairCreated event
+type Created struct {
+	Pair  common.Address
+	Block uint64
+}
+
+// WatchCreated sends every pair created from block from onwards on out until ctx
+// is cancelled or the connection fails. On websocket endpoints it subscribes to
+// PairCreated; on HTTP endpoints, where subscriptions are unsupported, it polls
+// for the events every pollInterval.
+func (f *Factory) WatchCreated(ctx context.Context, from uint64, pollInterval time.Duration, out chan<- Created) error {
+	events := make(chan *uniswapv2.IUniswapV2FactoryPairCreated, 64)
+	sub, err := f.contract.WatchPairCreated(&bind.WatchOpts{Context: ctx}, events, nil, nil)
+	if errors.Is(err, rpc.ErrNotificationsUnsupported) {
+		return f.pollCreated(ctx, from, pollInterval, out)
+	}
+	if err != nil {
+		return fmt.Errorf("subscribing to PairCreated: %w", err)
+	}
+	defer sub.Unsubscribe()
+
+	// The subscription only carries new events, so catch up on the blocks since
+	// from. Pairs created while catching up may arrive twice and are only sent once.
+	seen := make(map[common.Address]bool)
+	head, err := f.backend.BlockNumber(ctx)
+	if err != nil {
+		return fmt.Errorf("reading head: %w", err)
+	}
+	if head >= from {
+		created, err := f.created(ctx, from, head)
+		if err != nil {
+			return err
+		}
+		for _, pair := range created {
+			seen[pair.Pair] = true
+			if !send(ctx, out, pair) {
+				return ctx.Err()
+			}
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case err := <-sub.Err():
+			return fmt.Errorf("PairCreated subscription: %w", err)
+
+		case event := <-events:
+			// A removed log belongs to a block that is no longer canonical
+			if event.Raw.Removed || seen[event.Pair] {
+				continue
+			}
+			seen[event.Pair] = true
+			if !send(ctx, out, Created{Pair: event.Pair, Block: event.Raw.BlockNumber}) {
+				return ctx.Err()
+			}
+		}
+	}
+}
+
+// pollCreated follows PairCreated with FilterLogs for endpoints that cannot subscribe
+func (f *Factory) pollCreated(ctx context.Context, from uint64, pollInterval time.Duration, out chan<- Created) error {
+	next := from
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		head, err := f.backend.BlockNumber(ctx)
+		if err != nil {
+			return fmt.Errorf("reading head: %w", err)
+		}
+
+		if head >= next {
+			created, err := f.created(ctx, next, head)
+			if err != nil {
+				return err
+			}
+			for _, pair := range created {
+				if !send(ctx, out, pair) {
+					return ctx.Err()
+				}
+			}
+			next = head + 1
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// created returns the pairs created between blocks from and to, inclusive
+func (f *Factory) created(ctx context.Context, from, to uint64) ([]Created, error) {
+	events, err := f.contract.FilterPairCreated(&bind.FilterOpts{Start: from, End: &to, Context: ctx}, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("filtering PairCreated logs: %w", err)
+	}
+	defer events.Close()
+
+	var created []Created
+	for events.Next() {
+		created = append(created, Created{Pair: events.Event.Pair, Block: events.Event.Raw.BlockNumber})
+	}
+	if err := events.Error(); err != nil {
+		return nil, fmt.Errorf("reading PairCreated logs: %w", err)
+	}
+
+	return created, nil
+}
+
+// send delivers a created pair unless ctx is cancelled first
+func send(ctx context.Context, out chan<- Created, pair Created) bool {
+	select {
+	case out <- pair:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,147 @@
+// Package hdwallet derives Ethereum accounts from a BIP-39 mnemonic along
+// BIP-32 derivation paths such as m/44'/60'/0'/0/0.
+package hdwallet
+
+import (
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/tyler-smith/go-bip39"
+)
+
+// DefaultPath is the first account of the standard Ethereum derivation path
+const DefaultPath = "m/44'/60'/0'/0/0"
+
+// HardenedOffset is the first hardened child index
+const HardenedOffset = 0x80000000
+
+var (
+	// ErrInvalidMnemonic is returned for a mnemonic that fails the BIP-39 checksum
+	ErrInvalidMnemonic = errors.New("invalid BIP-39 mnemonic")
+
+	// ErrInvalidKey is returned in the (astronomically unlikely) case that an
+	// index derives an invalid key; BIP-32 says to skip to the next index
+	ErrInvalidKey = errors.New("derived key is invalid for this index")
+)
+
+// NewMnemonic generates a random mnemonic: 128 bits of entropy give 12 words, 256 bits give 24
+func NewMnemonic(bits int) (string, error) {
+	entropy, err := bip39.NewEntropy(bits)
+	if err != nil {
+		return "", err
+	}
+
+	return bip39.NewMnemonic(entropy)
+}
+
+// Seed checks a mnemonic and turns it into a BIP-39 seed with an optional passphrase
+func Seed(mnemonic, passphrase string) ([]byte, error) {
+	mnemonic = strings.Join(strings.Fields(mnemonic), " ")
+	if !bip39.IsMnemonicValid(mnemonic) {
+		return nil, ErrInvalidMnemonic
+	}
+
+	return bip39.NewSeed(mnemonic, passphrase), nil
+}
+
+// Key is an extended private key: a secp256k1 key and its chain code
+type Key struct {
+	key       []byte
+	chainCode []byte
+}
+
+// NewMaster derives the master key of a seed
+func NewMaster(seed []byte) (*Key, error) {
+	mac := hmac.New(sha512.New, []byte("Bitcoin seed"))
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+
+	master := &Key{key: sum[:32], chainCode: sum[32:]}
+	if !validKey(new(big.Int).SetBytes(master.key)) {
+		return nil, ErrInvalidKey
+	}
+
+	return master, nil
+}
+
+// Child derives the child key at an index. Indices from HardenedOffset up are hardened.
+func (k *Key) Child(index uint32) (*Key, error) {
+	var data []byte
+	if index >= HardenedOffset {
+		data = append([]byte{0}, k.key...)
+	} else {
+		private, err := crypto.ToECDSA(k.key)
+		if err != nil {
+			return nil, err
+		}
+		data = crypto.CompressPubkey(&private.PublicKey)
+	}
+	data = binary.BigEndian.AppendUint32(data, index)
+
+	mac := hmac.New(sha512.New, k.chainCode)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+
+	tweak := new(big.Int).SetBytes(sum[:32])
+	if tweak.Cmp(crypto.S256().Params().N) >= 0 {
+		return nil, ErrInvalidKey
+	}
+
+	child := tweak.Add(tweak, new(big.Int).SetBytes(k.key))
+	child.Mod(child, crypto.S256().Params().N)
+	if !validKey(child) {
+		return nil, ErrInvalidKey
+	}
+
+	return &Key{key: child.FillBytes(make([]byte, 32)), chainCode: sum[32:]}, nil
+}
+
+// Derive follows a derivation path from this key
+func (k *Key) Derive(path accounts.DerivationPath) (*Key, error) {
+	key := k
+	for _, index := range path {
+		var err error
+		if key, err = key.Child(index); err != nil {
+			return nil, err
+		}
+	}
+
+	return key, nil
+}
+
+// PrivateKey returns the key as an Ethereum private key
+func (k *Key) PrivateKey() (*ecdsa.PrivateKey, error) {
+	return crypto.ToECDSA(k.key)
+}
+
+// DeriveKey derives the private key at a path such as m/44'/60'/0'/0/0 from a seed
+func DeriveKey(seed []byte, path string) (*ecdsa.PrivateKey, error) {
+	parsed, err := accounts.ParseDerivationPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	master, err := NewMaster(seed)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := master.Derive(parsed)
+	if err != nil {
+		return nil, err
+	}
+
+	return key.PrivateKey()
+}
+
+// validKey reports whether a scalar is a usable secp256k1 private key
+func validKey(k *big.Int) bool {
+	return k.Sign() > 0 && k.Cmp(crypto.S256().Params().N) < 0
+}
@@ -0,0 +1,136 @@
+package hdwallet
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// testMnemonic is the all-"abandon" BIP-39 test mnemonic
+const testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+// decode parses a hex string from the test vectors
+func decode(t *testing.T, s string) []byte {
+	t.Helper()
+
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// BIP-32 test vectors 1 to 3, checking the private key and chain code of every step
+func TestBIP32Vectors(t *testing.T) {
+	type step struct {
+		path      string
+		key       string
+		chainCode string
+	}
+	vectors := []struct {
+		seed  string
+		steps []step
+	}{
+		{"000102030405060708090a0b0c0d0e0f", []step{
+			{"m", "e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35", "873dff81c02f525623fd1fe5167eac3a55a049de3d314bb42ee227ffed37d508"},
+			{"m/0'", "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea", "47fdacbd0f1097043b78c63c20c34ef4ed9a111d980047ad16282c7ae6236141"},
+			{"m/0'/1", "3c6cb8d0f6a264c91ea8b5030fadaa8e538b020f0a387421a12de9319dc93368", "2a7857631386ba23dacac34180dd1983734e444fdbf774041578e9b6adb37c19"},
+			{"m/0'/1/2'", "cbce0d719ecf7431d88e6a89fa1483e02e35092af60c042b1df2ff59fa424dca", "04466b9cc8e161e966409ca52986c584f07e9dc81f735db683c3ff6ec7b1503f"},
+			{"m/0'/1/2'/2", "0f479245fb19a38a1954c5c7c0ebab2f9bdfd96a17563ef28a6a4b1a2a764ef4", "cfb71883f01676f587d023cc53a35bc7f88f724b1f8c2892ac1275ac822a3edd"},
+			{"m/0'/1/2'/2/1000000000", "471b76e389e528d6de6d816857e012c5455051cad6660850e58372a6c3e6e7c8", "c783e67b921d2beb8f6b389cc646d7263b4145701dadd2161548a8b078e65e9e"},
+		}},
+		{"fffcf9f6f3f0edeae7e4e1dedbd8d5d2cfccc9c6c3c0bdbab7b4b1aeaba8a5a29f9c999693908d8a8784817e7b7875726f6c696663605d5a5754514e4b484542", []step{
+			{"m", "4b03d6fc340455b363f51020ad3ecca4f0850280cf436c70c727923f6db46c3e", "60499f801b896d83179a4374aeb7822aaeaceaa0db1f85ee3e904c4defbd9689"},
+			{"m/0", "abe74a98f6c7eabee0428f53798f0ab8aa1bd37873999041703c742f15ac7e1e", "f0909affaa7ee7abe5dd4e100598d4dc53cd709d5a5c2cac40e7412f232f7c9c"},
+			{"m/0/2147483647'", "877c779ad9687164e9c2f4f0f4ff0340814392330693ce95a58fe18fd52e6e93", "be17a268474a6bb9c61e1d720cf6215e2a88c5406c4aee7b38547f585c9a37d9"},
+			{"m/0/2147483647'/1", "704addf544a06e5ee4bea37098463c23613da32020d604506da8c0518e1da4b7", "f366f48f1ea9f2d1d3fe958c95ca84ea18e4c4ddb9366c336c927eb246fb38cb"},
+		}},
+		// The master key has a leading zero byte, which must be kept
+		{"4b381541583be4423346c643850da4b320e46a87ae3d2a4e6da11eba819cd4acba45d239319ac14f863b8d5ab5a0d0c64d2e8a1e7d1457df2e5a3c51c73235be", []step{
+			{"m", "00ddb80b067e0d4993197fe10f2657a844a384589847602d56f0c629c81aae32", "01d28a3e53cffa419ec122c968b3259e16b65076495494d97cae10bbfec3c36f"},
+			{"m/0'", "491f7a2eebc7b57028e0d3faa0acda02e75c33b03c48fb288c41e2ea44e1daef", "e5fea12a97b927fc9dc3d2cb0d1ea1cf50aa5a1fdc1f933e8906bb38df3377bd"},
+		}},
+	}
+
+	for _, vector := range vectors {
+		master, err := NewMaster(decode(t, vector.seed))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, step := range vector.steps {
+			key := master
+			if step.path != "m" {
+				path, err := accounts.ParseDerivationPath(step.path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if key, err = master.Derive(path); err != nil {
+					t.Fatalf("%s: %v", step.path, err)
+				}
+			}
+			if got := hex.EncodeToString(key.key); got != step.key {
+				t.Errorf("%s: key = %s, want %s", step.path, got, step.key)
+			}
+			if got := hex.EncodeToString(key.chainCode); got != step.chainCode {
+				t.Errorf("%s: chain code = %s, want %s", step.path, got, step.chainCode)
+			}
+		}
+	}
+}
+
+func TestMnemonicAccount(t *testing.T) {
+	seed, err := Seed(testMnemonic, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := DeriveKey(seed, DefaultPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := crypto.PubkeyToAddress(key.PublicKey).Hex(), "0x9858EfFD232B4033E47d90003D41EC34EcaEda94"; got != want {
+		t.Errorf("account at %s = %s, want %s", DefaultPath, got, want)
+	}
+
+	// BIP-39 vector for the same mnemonic with the passphrase TREZOR, with extra spaces that Seed tidies up
+	seed, err = Seed("  "+strings.ReplaceAll(testMnemonic, " ", "  ")+"\n", "TREZOR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04"
+	if got := hex.EncodeToString(seed); got != want {
+		t.Errorf("seed = %s, want %s", got, want)
+	}
+}
+
+func TestMnemonicErrors(t *testing.T) {
+	// Swapping the last word breaks the checksum
+	bad := strings.Replace(testMnemonic, "about", "abandon", 1)
+	if _, err := Seed(bad, ""); !errors.Is(err, ErrInvalidMnemonic) {
+		t.Errorf("Seed with a bad checksum: error = %v, want %v", err, ErrInvalidMnemonic)
+	}
+
+	seed, err := Seed(testMnemonic, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := DeriveKey(seed, "m/44'/60'/x"); err == nil {
+		t.Error("an invalid path was accepted")
+	}
+
+	for _, bits := range []int{128, 256} {
+		mnemonic, err := NewMnemonic(bits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if words := len(strings.Fields(mnemonic)); words != bits/128*12 {
+			t.Errorf("%d bits gave %d words", bits, words)
+		}
+		if _, err := Seed(mnemonic, ""); err != nil {
+			t.Errorf("a new mnemonic does not validate: %v", err)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package hdwallet
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ManifestDir is the subdirectory of a keystore directory that holds HD wallet
+// manifests. The keystore ignores subdirectories when scanning for accounts.
+const ManifestDir = "hd"
+
+// DefaultBasePath is the path accounts are derived under, one index per account
+const DefaultBasePath = "m/44'/60'/0'/0"
+
+// Manifest records an HD wallet: its seed, encrypted like a keystore key, and
+// the accounts derived from it so far. Holding the encrypted seed lets more
+// accounts be derived later with the password instead of the mnemonic.
+type Manifest struct {
+	ID       string              `json:"id"`
+	BasePath string              `json:"basePath"`
+	Created  time.Time           `json:"created"`
+	Seed     keystore.CryptoJSON `json:"seed"`
+	Accounts []Account           `json:"accounts"`
+}
+
+// Account is one derived account recorded in a manifest
+type Account struct {
+	Index   uint32         `json:"index"`
+	Path    string         `json:"path"`
+	Address common.Address `json:"address"`
+	File    string         `json:"file"`
+}
+
+// NewManifest encrypts a seed for a new HD wallet
+func NewManifest(seed []byte, password string, scryptN, scryptP int) (*Manifest, error) {
+	encrypted, err := keystore.EncryptDataV3(seed, []byte(password), scryptN, scryptP)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Manifest{BasePath: DefaultBasePath, Created: time.Now().UTC(), Seed: encrypted}, nil
+}
+
+// LoadManifest reads the manifest of the HD wallet with the given ID from a keystore directory
+func LoadManifest(keystoreDir, id string) (*Manifest, error) {
+	data, err := os.ReadFile(ManifestPath(keystoreDir, id))
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &Manifest{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("decoding manifest: %w", err)
+	}
+
+	return manifest, nil
+}
+
+// ManifestPath returns where the manifest of an HD wallet is stored
+func ManifestPath(keystoreDir, id string) string {
+	return filepath.Join(keystoreDir, ManifestDir, strings.ToLower(id)+".json")
+}
+
+// Save writes the manifest into a keystore directory, readable only by the owner
+func (m *Manifest) Save(keystoreDir string) error {
+	if err := os.MkdirAll(filepath.Join(keystoreDir, ManifestDir), 0o700); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(ManifestPath(keystoreDir, m.ID), data, 0o600)
+}
+
+// Unlock decrypts the seed and returns the master key
+func (m *Manifest) Unlock(password string) (*Key, error) {
+	seed, err := keystore.DecryptDataV3(m.Seed, password)
+	if err != nil {
+		return nil, fmt.Errorf("decrypting seed: %w", err)
+	}
+
+	return NewMaster(seed)
+}
+
+// NextIndex returns the first index no account has been derived at yet
+func (m *Manifest) NextIndex() uint32 {
+	next := uint32(0)
+	for _, account := range m.Accounts {
+		if account.Index >= next {
+			next = account.Index + 1
+		}
+	}
+
+	return next
+}
+
+// AccountPath returns the derivation path of the account at an index
+func (m *Manifest) AccountPath(index uint32) (string, accounts.DerivationPath, error) {
+	path := fmt.Sprintf("%s/%d", m.BasePath, index)
+
+	parsed, err := accounts.ParseDerivationPath(path)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return path, parsed, nil
+}
@@ -0,0 +1,112 @@
+// Package password reads the password that unlocks a keystore from a file,
+// an environment variable or an interactive prompt, so it never has to be
+// passed on the command line.
+package password
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// EnvVar is the environment variable read when no password file is given
+const EnvVar = "TRADEBOT_KEYSTORE_PASSWORD"
+
+// ErrNoTerminal is returned when a password would have to be prompted for but stdin is not a terminal
+var ErrNoTerminal = errors.New("no password given and stdin is not a terminal: use --password-file or set " + EnvVar)
+
+// ErrNoTerminalNew is returned when a new password would have to be prompted for but stdin is not a terminal
+var ErrNoTerminalNew = errors.New("no new password given and stdin is not a terminal: use --password-file")
+
+// ErrMismatch is returned when a confirmed password is not typed the same way twice
+var ErrMismatch = errors.New("passwords do not match")
+
+// Options selects where a password is read from. The first source that is set and non-empty wins:
+// File, then the EnvVar environment variable, then an interactive prompt. Passwords being set
+// are only read from File or the prompt, as EnvVar holds the password of existing keystores.
+type Options struct {
+	File    string // path to a file holding the password
+	Prompt  string // label shown when prompting, e.g. "Keystore password"
+	Confirm bool   // ask twice when prompting, for passwords being set
+}
+
+// Read returns the password from the first available source
+func Read(opts Options) (string, error) {
+	if opts.File != "" {
+		return FromFile(opts.File)
+	}
+	if opts.Confirm {
+		return PromptConfirm(opts.Prompt)
+	}
+
+	// An empty variable counts as unset, as an empty password cannot unlock a keystore
+	if password := os.Getenv(EnvVar); password != "" {
+		return password, nil
+	}
+
+	return Prompt(opts.Prompt)
+}
+
+// FromFile reads a password from a file, ignoring a trailing newline
+func FromFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("reading password file: %w", err)
+	}
+
+	password := strings.TrimRight(string(data), "\r\n")
+	if password == "" {
+		return "", errors.New("password file is empty")
+	}
+
+	return password, nil
+}
+
+// Prompt asks for a password on the terminal without echoing it
+func Prompt(label string) (string, error) {
+	if label == "" {
+		label = "Password"
+	}
+
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return "", ErrNoTerminal
+	}
+
+	fmt.Fprintf(os.Stderr, "%s: ", label)
+	input, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("reading password: %w", err)
+	}
+
+	return string(input), nil
+}
+
+// PromptConfirm asks for a new password twice and checks both entries match
+func PromptConfirm(label string) (string, error) {
+	if label == "" {
+		label = "Password"
+	}
+
+	password, err := Prompt(label)
+	if errors.Is(err, ErrNoTerminal) {
+		return "", ErrNoTerminalNew
+	}
+	if err != nil {
+		return "", err
+	}
+
+	confirmation, err := Prompt("Confirm " + strings.ToLower(label[:1]) + label[1:])
+	if err != nil {
+		return "", err
+	}
+
+	if password != confirmation {
+		return "", ErrMismatch
+	}
+
+	return password, nil
+}
@@ -0,0 +1,40 @@
+package password
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "password")
+	if err := os.WriteFile(path, []byte("from file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(EnvVar, "from env")
+
+	tests := []struct {
+		name string
+		opts Options
+		want string
+		err  error
+	}{
+		{"file first", Options{File: path}, "from file", nil},
+		{"new password from file", Options{File: path, Confirm: true}, "from file", nil},
+		{"environment", Options{}, "from env", nil},
+		{"new password ignores the environment", Options{Confirm: true}, "", ErrNoTerminalNew},
+	}
+	for _, test := range tests {
+		got, err := Read(test.opts)
+		if got != test.want || !errors.Is(err, test.err) {
+			t.Errorf("%s: Read = %q, %v, want %q, %v", test.name, got, err, test.want, test.err)
+		}
+	}
+
+	// An empty variable is unset, and tests have no terminal to prompt on
+	t.Setenv(EnvVar, "")
+	if _, err := Read(Options{}); !errors.Is(err, ErrNoTerminal) {
+		t.Errorf("empty %s: error = %v, want ErrNoTerminal", EnvVar, err)
+	}
+}
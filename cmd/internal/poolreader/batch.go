@@ -0,0 +1,177 @@
+package poolreader
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/contracts/multicall3"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/contracts/uniswapv2"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// multicallChunkSize caps the number of getReserves() calls packed into one aggregate3.
+// Every chunk is pinned to the same block, so chunking never mixes states.
+const multicallChunkSize = 200
+
+// Batch is a set of pool reserves read at a single block
+type Batch struct {
+	BlockNumber uint64
+	Reserves    map[common.Address]*Reserves
+	Errors      map[common.Address]error // pools whose read failed
+	Reorg       bool                     // pools were re-read because a reorg removed their Sync logs
+}
+
+// ReservesBatch reads every pool's reserves pinned to the latest block.
+/*
+	Uses a single Multicall3 aggregate3 eth_call when the multicall contract is deployed,
+	and falls back to one getReserves() call per pool (still pinned to the same block)
+	when it is not, so the ratios are always consistent with each other.
+*/
+func (l *Live) ReservesBatch(ctx context.Context, pools []common.Address) (*Batch, error) {
+	blockNumber, err := l.backend.BlockNumber(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("reading block number: %w", err)
+	}
+
+	return l.reservesBatchAt(ctx, pools, blockNumber)
+}
+
+// reservesBatchAt reads every pool's reserves as of one block
+func (l *Live) reservesBatchAt(ctx context.Context, pools []common.Address, blockNumber uint64) (*Batch, error) {
+	opts := &bind.CallOpts{Context: ctx, BlockNumber: new(big.Int).SetUint64(blockNumber)}
+
+	batch := newBatch(blockNumber)
+
+	useMulticall, err := l.multicallDeployed(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if !useMulticall {
+		for _, pool := range pools {
+			reserves, err := l.reservesAt(opts, pool)
+			if err != nil {
+				batch.Errors[pool] = err
+				continue
+			}
+			batch.Reserves[pool] = reserves
+		}
+		return batch, nil
+	}
+
+	for start := 0; start < len(pools); start += multicallChunkSize {
+		end := min(start+multicallChunkSize, len(pools))
+		if err := l.multicallReserves(opts, pools[start:end], batch); err != nil {
+			return nil, err
+		}
+	}
+
+	return batch, nil
+}
+
+// UseMulticall changes the Multicall3 address used for batch reads
+func (l *Live) UseMulticall(address common.Address) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.multicall = address
+	l.multicallChecked = false
+}
+
+// multicallDeployed reports whether there is contract code at the multicall address
+func (l *Live) multicallDeployed(opts *bind.CallOpts) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.multicallChecked {
+		return l.multicallAvailable, nil
+	}
+
+	code, err := l.backend.CodeAt(opts.Context, l.multicall, opts.BlockNumber)
+	if err != nil {
+		return false, fmt.Errorf("checking multicall contract: %w", err)
+	}
+
+	l.multicallChecked = true
+	l.multicallAvailable = len(code) > 0
+
+	return l.multicallAvailable, nil
+}
+
+// multicallReserves packs getReserves() for each pool into one aggregate3 call
+func (l *Live) multicallReserves(opts *bind.CallOpts, pools []common.Address, batch *Batch) error {
+	pairABI, err := uniswapv2.IUniswapV2PairMetaData.GetAbi()
+	if err != nil {
+		return err
+	}
+
+	callData, err := pairABI.Pack("getReserves")
+	if err != nil {
+		return err
+	}
+
+	calls := make([]multicall3.Multicall3Call3, len(pools))
+	for i, pool := range pools {
+		calls[i] = multicall3.Multicall3Call3{Target: pool, AllowFailure: true, CallData: callData}
+	}
+
+	caller, err := multicall3.NewMulticall3Caller(l.multicall, l.backend)
+	if err != nil {
+		return fmt.Errorf("binding multicall contract: %w", err)
+	}
+
+	// aggregate3 is declared payable, so it has to go through the raw caller to be eth_call'd
+	var out []interface{}
+	raw := &multicall3.Multicall3CallerRaw{Contract: caller}
+	if err := raw.Call(opts, &out, "aggregate3", calls); err != nil {
+		return fmt.Errorf("calling aggregate3: %w", err)
+	}
+	results := *abi.ConvertType(out[0], new([]multicall3.Multicall3Result)).(*[]multicall3.Multicall3Result)
+	if len(results) != len(pools) {
+		return fmt.Errorf("aggregate3 returned %d results for %d calls", len(results), len(pools))
+	}
+
+	for i, pool := range pools {
+		if !results[i].Success {
+			batch.Errors[pool] = fmt.Errorf("getReserves reverted")
+			continue
+		}
+
+		values, err := pairABI.Unpack("getReserves", results[i].ReturnData)
+		if err != nil {
+			batch.Errors[pool] = fmt.Errorf("decoding getReserves: %w", err)
+			continue
+		}
+
+		batch.Reserves[pool] = &Reserves{
+			Pool:      pool,
+			Reserve0:  values[0].(*big.Int),
+			Reserve1:  values[1].(*big.Int),
+			Timestamp: values[2].(uint32),
+		}
+	}
+
+	return nil
+}
+
+// ReservesBatch returns every requested pool from the fake at its current block
+func (f *Fake) ReservesBatch(ctx context.Context, pools []common.Address) (*Batch, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	batch := newBatch(f.block)
+
+	for _, pool := range pools {
+		r, ok := f.reserves[pool]
+		if !ok {
+			batch.Errors[pool] = fmt.Errorf("unknown pool %s", pool.Hex())
+			continue
+		}
+		batch.Reserves[pool] = r.copy()
+	}
+
+	return batch, nil
+}
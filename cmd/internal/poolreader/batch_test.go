@@ -0,0 +1,114 @@
+package poolreader
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/testchain"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestReservesBatchWithMulticall(t *testing.T) {
+	testReservesBatch(t, true)
+}
+
+func TestReservesBatchWithoutMulticall(t *testing.T) {
+	testReservesBatch(t, false)
+}
+
+// testReservesBatch syncs two pools in turn and checks that a batch pinned to
+// each block sees both pools as of that block, through aggregate3 or the
+// per-pool fallback
+func testReservesBatch(t *testing.T, multicall bool) {
+	genesis := testchain.NewGenesis(t)
+	poolA := deployPool(genesis,
+		Token{Symbol: "eUSD", Decimals: 6}, Token{Symbol: "eEUR", Decimals: 18},
+		units(1000, 6), units(900, 18))
+	poolB := deployPool(genesis,
+		Token{Symbol: "eGBP", Decimals: 18}, Token{Symbol: "eJPY", Decimals: 18},
+		units(1000, 18), units(150_000, 18))
+	for _, pool := range []testPool{poolA, poolB} {
+		token0, _, _, _ := pool.sorted()
+		genesis.Mint(token0.Address, genesis.Account(), units(10, int64(token0.Decimals)))
+	}
+	multicallAddress := common.HexToAddress("0x000000000000000000000000000000000000ca11")
+	if multicall {
+		multicallAddress = genesis.Multicall()
+	}
+	chain := genesis.Start(t)
+
+	ctx := context.Background()
+	live := NewLive(chain.Client())
+	live.UseMulticall(multicallAddress)
+
+	// syncPool adds 10 tokens to a pool's reserve0 and returns its reserves
+	syncPool := func(pool testPool) [2]*big.Int {
+		token0, _, reserve0, reserve1 := pool.sorted()
+		added := units(10, int64(token0.Decimals))
+		chain.Transfer(t, token0.Address, pool.address, added)
+		chain.Sync(t, pool.address)
+		return [2]*big.Int{new(big.Int).Add(reserve0, added), reserve1}
+	}
+	initial := func(pool testPool) [2]*big.Int {
+		_, _, reserve0, reserve1 := pool.sorted()
+		return [2]*big.Int{reserve0, reserve1}
+	}
+
+	start := chain.BlockNumber(t)
+	syncedA := syncPool(poolA)
+	afterA := chain.BlockNumber(t)
+	syncedB := syncPool(poolB)
+	afterB := chain.BlockNumber(t)
+
+	notPair := chain.Account()
+	pools := []common.Address{poolA.address, poolB.address, notPair}
+	cases := []struct {
+		block        uint64
+		poolA, poolB [2]*big.Int
+	}{
+		{start, initial(poolA), initial(poolB)},
+		{afterA, syncedA, initial(poolB)},
+		{afterB, syncedA, syncedB},
+	}
+	for _, c := range cases {
+		batch, err := live.At(c.block).ReservesBatch(ctx, pools)
+		if err != nil {
+			t.Fatalf("block %d: %v", c.block, err)
+		}
+		checkBatch(t, batch, c.block, map[common.Address][2]*big.Int{poolA.address: c.poolA, poolB.address: c.poolB})
+		if _, ok := batch.Errors[notPair]; !ok || len(batch.Errors) != 1 {
+			t.Errorf("block %d: errors = %v, want only %s", c.block, batch.Errors, notPair.Hex())
+		}
+	}
+
+	latest, err := live.ReservesBatch(ctx, pools[:2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkBatch(t, latest, afterB, map[common.Address][2]*big.Int{poolA.address: syncedA, poolB.address: syncedB})
+
+	if live.multicallAvailable != multicall {
+		t.Errorf("multicall used = %t, want %t", live.multicallAvailable, multicall)
+	}
+}
+
+// checkBatch compares a batch with the reserves expected at a block
+func checkBatch(t *testing.T, batch *Batch, block uint64, want map[common.Address][2]*big.Int) {
+	t.Helper()
+
+	if batch.BlockNumber != block {
+		t.Errorf("batch block = %d, want %d", batch.BlockNumber, block)
+	}
+	for pool, reserves := range want {
+		got, ok := batch.Reserves[pool]
+		if !ok {
+			t.Errorf("block %d: no reserves for %s (error %v)", block, pool.Hex(), batch.Errors[pool])
+			continue
+		}
+		if got.Reserve0.Cmp(reserves[0]) != 0 || got.Reserve1.Cmp(reserves[1]) != 0 {
+			t.Errorf("block %d: %s reserves = %s/%s, want %s/%s", block, pool.Hex(),
+				got.Reserve0, got.Reserve1, reserves[0], reserves[1])
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package poolreader
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/contracts/erc20"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/contracts/multicall3"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/contracts/uniswapv2"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Backend is the subset of an Ethereum client the live reader needs. Both
+// *ethclient.Client and go-ethereum's simulated backend client satisfy it.
+type Backend interface {
+	bind.ContractCaller
+	BlockNumber(ctx context.Context) (uint64, error)
+}
+
+// Live reads pool state from the chain through the Uniswap V2 pair binding
+type Live struct {
+	backend Backend
+
+	// Token metadata never changes for a deployed pair, so it is cached
+	mu     sync.Mutex
+	tokens map[common.Address][2]Token
+
+	// Multicall3 contract used by ReservesBatch, and whether it has code on this chain
+	multicall          common.Address
+	multicallChecked   bool
+	multicallAvailable bool
+}
+
+// NewLive creates a reader on top of an Ethereum client, batching through the canonical Multicall3
+func NewLive(backend Backend) *Live {
+	return &Live{
+		backend:   backend,
+		tokens:    make(map[common.Address][2]Token),
+		multicall: multicall3.DefaultAddress,
+	}
+}
+
+// Backend returns the client the reader was created with
+func (l *Live) Backend() Backend {
+	return l.backend
+}
+
+// Reserves calls getReserves() on the pair
+func (l *Live) Reserves(ctx context.Context, pool common.Address) (*Reserves, error) {
+	return l.reservesAt(&bind.CallOpts{Context: ctx}, pool)
+}
+
+// reservesAt calls getReserves() on the pair at the block given in opts
+func (l *Live) reservesAt(opts *bind.CallOpts, pool common.Address) (*Reserves, error) {
+	pair, err := uniswapv2.NewIUniswapV2PairCaller(pool, l.backend)
+	if err != nil {
+		return nil, fmt.Errorf("binding pair contract: %w", err)
+	}
+
+	// getReserves() returns (uint112 reserve0, uint112 reserve1, uint32 blockTimestampLast)
+	onChain, err := pair.GetReserves(opts)
+	if err != nil {
+		return nil, fmt.Errorf("calling getReserves: %w", err)
+	}
+
+	reserves := &Reserves{
+		Pool:      pool,
+		Reserve0:  onChain.Reserve0,
+		Reserve1:  onChain.Reserve1,
+		Timestamp: onChain.BlockTimestampLast,
+	}
+
+	return reserves, nil
+}
+
+// Tokens calls token0() and token1() on the pair, then symbol() and decimals() on each token
+func (l *Live) Tokens(ctx context.Context, pool common.Address) (Token, Token, error) {
+	l.mu.Lock()
+	cached, ok := l.tokens[pool]
+	l.mu.Unlock()
+	if ok {
+		return cached[0], cached[1], nil
+	}
+
+	pair, err := uniswapv2.NewIUniswapV2PairCaller(pool, l.backend)
+	if err != nil {
+		return Token{}, Token{}, fmt.Errorf("binding pair contract: %w", err)
+	}
+	opts := &bind.CallOpts{Context: ctx}
+
+	token0, err := pair.Token0(opts)
+	if err != nil {
+		return Token{}, Token{}, fmt.Errorf("calling token0: %w", err)
+	}
+
+	token1, err := pair.Token1(opts)
+	if err != nil {
+		return Token{}, Token{}, fmt.Errorf("calling token1: %w", err)
+	}
+
+	meta0, err := l.tokenMetadata(opts, token0)
+	if err != nil {
+		return Token{}, Token{}, err
+	}
+
+	meta1, err := l.tokenMetadata(opts, token1)
+	if err != nil {
+		return Token{}, Token{}, err
+	}
+
+	l.mu.Lock()
+	l.tokens[pool] = [2]Token{meta0, meta1}
+	l.mu.Unlock()
+
+	return meta0, meta1, nil
+}
+
+// BlockNumber returns the latest block number from the client
+func (l *Live) BlockNumber(ctx context.Context) (uint64, error) {
+	return l.backend.BlockNumber(ctx)
+}
+
+// tokenMetadata reads symbol() and decimals() from an ERC-20 token
+func (l *Live) tokenMetadata(opts *bind.CallOpts, address common.Address) (Token, error) {
+	token, err := erc20.NewIERC20Caller(address, l.backend)
+	if err != nil {
+		return Token{}, fmt.Errorf("binding token %s: %w", address.Hex(), err)
+	}
+
+	symbol, err := token.Symbol(opts)
+	if err != nil {
+		return Token{}, fmt.Errorf("calling symbol on %s: %w", address.Hex(), err)
+	}
+
+	decimals, err := token.Decimals(opts)
+	if err != nil {
+		return Token{}, fmt.Errorf("calling decimals on %s: %w", address.Hex(), err)
+	}
+
+	return Token{Address: address, Symbol: symbol, Decimals: decimals}, nil
+}
@@ -0,0 +1,223 @@
+package poolreader
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/registry"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/testchain"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// units returns amount whole tokens in base units of a token with the given decimals
+func units(amount int64, decimals int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(amount), new(big.Int).Exp(big.NewInt(10), big.NewInt(decimals), nil))
+}
+
+// testPool is a pair deployed on a simulated chain with the tokens it was created from
+type testPool struct {
+	address            common.Address
+	tokenA, tokenB     Token
+	reserveA, reserveB *big.Int
+}
+
+// deployPool adds a pair of two new tokens to the genesis
+func deployPool(genesis *testchain.Genesis, a, b Token, reserveA, reserveB *big.Int) testPool {
+	a.Address = genesis.Token(a.Symbol, a.Decimals)
+	b.Address = genesis.Token(b.Symbol, b.Decimals)
+
+	return testPool{
+		address:  genesis.Pair(a.Address, b.Address, reserveA, reserveB),
+		tokenA:   a,
+		tokenB:   b,
+		reserveA: reserveA,
+		reserveB: reserveB,
+	}
+}
+
+// sorted returns the pool's tokens and reserves in token0, token1 order
+func (p testPool) sorted() (Token, Token, *big.Int, *big.Int) {
+	if p.tokenB.Address.Cmp(p.tokenA.Address) < 0 {
+		return p.tokenB, p.tokenA, p.reserveB, p.reserveA
+	}
+
+	return p.tokenA, p.tokenB, p.reserveA, p.reserveB
+}
+
+func TestLiveReservesAndTokens(t *testing.T) {
+	genesis := testchain.NewGenesis(t)
+	pool := deployPool(genesis,
+		Token{Symbol: "eUSD", Decimals: 6}, Token{Symbol: "eEUR", Decimals: 18},
+		units(1_000_000, 6), units(920_000, 18))
+	chain := genesis.Start(t)
+
+	ctx := context.Background()
+	live := NewLive(chain.Client())
+	token0, token1, reserve0, reserve1 := pool.sorted()
+
+	reserves, err := live.Reserves(ctx, pool.address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reserves.Pool != pool.address || reserves.Reserve0.Cmp(reserve0) != 0 || reserves.Reserve1.Cmp(reserve1) != 0 {
+		t.Errorf("reserves = %s %s/%s, want %s %s/%s", reserves.Pool.Hex(), reserves.Reserve0, reserves.Reserve1,
+			pool.address.Hex(), reserve0, reserve1)
+	}
+
+	got0, got1, err := live.Tokens(ctx, pool.address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got0 != token0 || got1 != token1 {
+		t.Errorf("tokens = %+v, %+v, want %+v, %+v", got0, got1, token0, token1)
+	}
+	if got0.Address.Cmp(got1.Address) >= 0 {
+		t.Errorf("token0 %s does not sort before token1 %s", got0.Address.Hex(), got1.Address.Hex())
+	}
+}
+
+func TestLiveReservesFollowSync(t *testing.T) {
+	genesis := testchain.NewGenesis(t)
+	pool := deployPool(genesis,
+		Token{Symbol: "eUSD", Decimals: 18}, Token{Symbol: "eGBP", Decimals: 18},
+		units(1000, 18), units(800, 18))
+	token0, _, _, _ := pool.sorted()
+	genesis.Mint(token0.Address, genesis.Account(), units(10, 18))
+	chain := genesis.Start(t)
+
+	ctx := context.Background()
+	live := NewLive(chain.Client())
+	before, err := live.Reserves(ctx, pool.address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chain.Transfer(t, token0.Address, pool.address, units(10, 18))
+	chain.Sync(t, pool.address)
+
+	after, err := live.Reserves(ctx, pool.address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := new(big.Int).Add(before.Reserve0, units(10, 18)); after.Reserve0.Cmp(want) != 0 {
+		t.Errorf("reserve0 after sync = %s, want %s", after.Reserve0, want)
+	}
+	if after.Reserve1.Cmp(before.Reserve1) != 0 {
+		t.Errorf("reserve1 after sync = %s, want %s", after.Reserve1, before.Reserve1)
+	}
+	if after.Timestamp == 0 {
+		t.Error("sync did not set blockTimestampLast")
+	}
+}
+
+func TestLiveRejectsNonPairs(t *testing.T) {
+	genesis := testchain.NewGenesis(t)
+	chain := genesis.Start(t)
+
+	ctx := context.Background()
+	live := NewLive(chain.Client())
+	if _, err := live.Reserves(ctx, chain.Account()); err == nil {
+		t.Error("Reserves of an account without code succeeded")
+	}
+	if _, _, err := live.Tokens(ctx, chain.Account()); err == nil {
+		t.Error("Tokens of an account without code succeeded")
+	}
+}
+
+func TestFakeReader(t *testing.T) {
+	ctx := context.Background()
+	pools := registry.Builtin()
+
+	// A pool whose registry entry declares its tokens' decimals
+	usdc := &registry.Pool{
+		Name:    "USDC_eUSD_Pool",
+		Address: common.HexToAddress("0x00000000000000000000000000000000000b00c1"),
+		Token0:  registry.Token{Symbol: "USDC", Address: common.HexToAddress("0x00000000000000000000000000000000000c0001"), Decimals: 6},
+		Token1:  registry.Token{Symbol: "eUSD"},
+	}
+	if err := pools.Add(usdc); err != nil {
+		t.Fatal(err)
+	}
+
+	fake := NewFake(pools)
+	again := NewFake(pools)
+
+	for _, pool := range pools.Pools() {
+		reserves, err := fake.Reserves(ctx, pool.Address)
+		if err != nil {
+			t.Fatalf("%s: %v", pool.Name, err)
+		}
+		token0, token1, err := fake.Tokens(ctx, pool.Address)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Reserves hold 500-1499 whole tokens in each token's own decimals
+		for token, reserve := range map[Token]*big.Int{token0: reserves.Reserve0, token1: reserves.Reserve1} {
+			decimals := int64(token.Decimals)
+			if reserve.Cmp(units(500, decimals)) < 0 || reserve.Cmp(units(1499, decimals)) > 0 {
+				t.Errorf("%s: %s reserve %s outside 500-1499 tokens of %d decimals", pool.Name, token.Symbol, reserve, token.Decimals)
+			}
+		}
+
+		repeated, err := again.Reserves(ctx, pool.Address)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if repeated.Reserve0.Cmp(reserves.Reserve0) != 0 || repeated.Reserve1.Cmp(reserves.Reserve1) != 0 {
+			t.Errorf("%s: reserves differ between two fakes", pool.Name)
+		}
+
+		if token0.Symbol != pool.Token0.Symbol || token1.Symbol != pool.Token1.Symbol {
+			t.Errorf("%s: tokens %s/%s, want %s/%s", pool.Name, token0.Symbol, token1.Symbol, pool.Token0.Symbol, pool.Token1.Symbol)
+		}
+		if token0.Address == usdc.Token0.Address && token0.Decimals != 6 {
+			t.Errorf("%s: USDC has %d decimals, want the registry's 6", pool.Name, token0.Decimals)
+		}
+		if pool != usdc && (token0.Decimals != 18 || token1.Decimals != 18) {
+			t.Errorf("%s: decimals %d/%d, want 18 for unknown tokens", pool.Name, token0.Decimals, token1.Decimals)
+		}
+		if token0.Address == (common.Address{}) || token0.Address == token1.Address {
+			t.Errorf("%s: token addresses %s/%s", pool.Name, token0.Address.Hex(), token1.Address.Hex())
+		}
+	}
+}
+
+func TestFakeSetReserves(t *testing.T) {
+	ctx := context.Background()
+	pools := registry.Builtin()
+	pool := pools.Pools()[0].Address
+	fake := NewFake(pools)
+
+	start, _ := fake.BlockNumber(ctx)
+	if start != fakeStartBlock {
+		t.Errorf("fake starts at block %d, want %d", start, fakeStartBlock)
+	}
+
+	// Reserves hands out copies
+	reserves, _ := fake.Reserves(ctx, pool)
+	reserves.Reserve0.SetInt64(1)
+	if unchanged, _ := fake.Reserves(ctx, pool); unchanged.Reserve0.Cmp(big.NewInt(1)) == 0 {
+		t.Error("changing returned reserves changed the fake")
+	}
+
+	if err := fake.SetReserves(pool, big.NewInt(7), big.NewInt(11)); err != nil {
+		t.Fatal(err)
+	}
+	reserves, _ = fake.Reserves(ctx, pool)
+	if reserves.Reserve0.Int64() != 7 || reserves.Reserve1.Int64() != 11 {
+		t.Errorf("reserves after SetReserves = %s/%s, want 7/11", reserves.Reserve0, reserves.Reserve1)
+	}
+	if block, _ := fake.BlockNumber(ctx); block != start+1 {
+		t.Errorf("block after SetReserves = %d, want %d", block, start+1)
+	}
+
+	unknown := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	if _, err := fake.Reserves(ctx, unknown); err == nil {
+		t.Error("Reserves of an unknown pool succeeded")
+	}
+	if err := fake.SetReserves(unknown, big.NewInt(1), big.NewInt(1)); err == nil {
+		t.Error("SetReserves of an unknown pool succeeded")
+	}
+}
@@ -0,0 +1,36 @@
+package poolreader
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Pinned is a live reader whose reserve reads are all made as of one block,
+// so a quote can be reproduced against historical state
+type Pinned struct {
+	*Live
+	block uint64
+}
+
+// At returns a reader that reads reserves as of the given block
+func (l *Live) At(blockNumber uint64) *Pinned {
+	return &Pinned{Live: l, block: blockNumber}
+}
+
+// Reserves calls getReserves() on the pair at the pinned block
+func (p *Pinned) Reserves(ctx context.Context, pool common.Address) (*Reserves, error) {
+	return p.reservesAt(&bind.CallOpts{Context: ctx, BlockNumber: new(big.Int).SetUint64(p.block)}, pool)
+}
+
+// ReservesBatch reads every pool's reserves at the pinned block
+func (p *Pinned) ReservesBatch(ctx context.Context, pools []common.Address) (*Batch, error) {
+	return p.reservesBatchAt(ctx, pools, p.block)
+}
+
+// BlockNumber returns the pinned block
+func (p *Pinned) BlockNumber(ctx context.Context) (uint64, error) {
+	return p.block, nil
+}
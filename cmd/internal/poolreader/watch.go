@@ -0,0 +1,362 @@
+package poolreader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"time"
+
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/contracts/uniswapv2"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// reorgWindow is how many blocks of history the polling watcher remembers for reorg detection
+const reorgWindow = 64
+
+// WatchBackend is the subset of an Ethereum client the Sync watcher needs.
+// *ethclient.Client satisfies it for both websocket and HTTP endpoints.
+type WatchBackend interface {
+	ethereum.LogFilterer
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error)
+}
+
+// SyncWatcher follows the Sync(uint112,uint112) events of a set of pools and
+// emits a Batch containing only the pools whose reserves moved.
+/*
+	On websocket endpoints it subscribes to new heads and Sync logs; on HTTP
+	endpoints, where subscriptions are unsupported, it polls FilterLogs every
+	PollInterval. Sync logs carry the new reserves directly, so no extra reads
+	are needed for normal updates. When a reorg removes logs the affected pools
+	are re-read through the reader and emitted in a batch with Reorg set.
+*/
+type SyncWatcher struct {
+	backend WatchBackend
+	reader  PoolReader
+	pools   []common.Address
+	syncID  common.Hash
+
+	// PollInterval is how often the HTTP fallback polls for new logs
+	PollInterval time.Duration
+}
+
+// NewSyncWatcher creates a watcher for the pools on top of a live reader's connection
+func NewSyncWatcher(reader PoolReader, pools []common.Address) (*SyncWatcher, error) {
+	live, ok := reader.(*Live)
+	if !ok {
+		return nil, errors.New("event-driven scanning needs a live RPC endpoint")
+	}
+
+	backend, ok := live.Backend().(WatchBackend)
+	if !ok {
+		return nil, errors.New("the RPC client does not support log filtering")
+	}
+
+	pairABI, err := uniswapv2.IUniswapV2PairMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+
+	watcher := &SyncWatcher{
+		backend:      backend,
+		reader:       reader,
+		pools:        pools,
+		syncID:       pairABI.Events["Sync"].ID,
+		PollInterval: 2 * time.Second,
+	}
+
+	return watcher, nil
+}
+
+// Run emits batches of moved pools on out until ctx is cancelled or the connection fails
+func (w *SyncWatcher) Run(ctx context.Context, out chan<- *Batch) error {
+	err := w.subscribe(ctx, out)
+	if errors.Is(err, rpc.ErrNotificationsUnsupported) {
+		return w.poll(ctx, out)
+	}
+
+	return err
+}
+
+// subscribe follows heads and Sync logs over a websocket subscription
+func (w *SyncWatcher) subscribe(ctx context.Context, out chan<- *Batch) error {
+	logs := make(chan types.Log, 256)
+	logSub, err := w.backend.SubscribeFilterLogs(ctx, w.query(nil, nil), logs)
+	if err != nil {
+		return err
+	}
+	defer logSub.Unsubscribe()
+
+	heads := make(chan *types.Header, 16)
+	headSub, err := w.backend.SubscribeNewHead(ctx, heads)
+	if err != nil {
+		return err
+	}
+	defer headSub.Unsubscribe()
+
+	// Logs for blocks newer than the last head are held back until that head
+	// arrives, so that every pool moved in a block is evaluated together
+	pending := make(map[uint64]*Batch)
+	var lastHead uint64
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case err := <-logSub.Err():
+			return fmt.Errorf("log subscription: %w", err)
+
+		case err := <-headSub.Err():
+			return fmt.Errorf("head subscription: %w", err)
+
+		case head := <-heads:
+			lastHead = head.Number.Uint64()
+			blocks := make([]uint64, 0, len(pending))
+			for blockNumber := range pending {
+				if blockNumber <= lastHead {
+					blocks = append(blocks, blockNumber)
+				}
+			}
+			slices.Sort(blocks)
+			for _, blockNumber := range blocks {
+				if !send(ctx, out, pending[blockNumber]) {
+					return ctx.Err()
+				}
+				delete(pending, blockNumber)
+			}
+
+		case log := <-logs:
+			if log.Removed {
+				// The block that emitted this log is no longer canonical: drop it and
+				// re-read the pool, since its previous state is not in the log
+				if batch, ok := pending[log.BlockNumber]; ok {
+					delete(batch.Reserves, log.Address)
+				}
+				if !send(ctx, out, w.reread(ctx, []common.Address{log.Address})) {
+					return ctx.Err()
+				}
+				continue
+			}
+
+			reserves, err := w.parseSync(log)
+			if err != nil {
+				continue
+			}
+
+			batch, ok := pending[log.BlockNumber]
+			if !ok {
+				batch = newBatch(log.BlockNumber)
+				pending[log.BlockNumber] = batch
+			}
+			batch.Reserves[log.Address] = reserves
+
+			// The head for this block has already been seen, so nothing else is coming for it
+			if log.BlockNumber <= lastHead {
+				if !send(ctx, out, batch) {
+					return ctx.Err()
+				}
+				delete(pending, log.BlockNumber)
+			}
+		}
+	}
+}
+
+// poll follows Sync logs with FilterLogs for endpoints that cannot subscribe
+func (w *SyncWatcher) poll(ctx context.Context, out chan<- *Batch) error {
+	head, err := w.backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("reading head: %w", err)
+	}
+
+	last := head.Number.Uint64()
+
+	// Hashes of processed blocks and the pools each one touched, for reorg detection
+	hashes := map[uint64]common.Hash{last: head.Hash()}
+	touched := make(map[uint64][]common.Address)
+
+	ticker := time.NewTicker(w.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		// If the last processed block changed hash, rewind to the fork point
+		// and re-read every pool whose Sync logs were in the dropped blocks
+		fork, reorged, err := w.findFork(ctx, last, hashes)
+		if err != nil {
+			return err
+		}
+		if reorged {
+			var pools []common.Address
+			for blockNumber, addresses := range touched {
+				if blockNumber > fork {
+					pools = append(pools, addresses...)
+					delete(touched, blockNumber)
+				}
+			}
+			for blockNumber := range hashes {
+				if blockNumber > fork {
+					delete(hashes, blockNumber)
+				}
+			}
+			if len(hashes) == 0 {
+				// The fork is deeper than the remembered window, so nothing can be trusted
+				pools = w.pools
+			}
+			if len(pools) > 0 && !send(ctx, out, w.reread(ctx, pools)) {
+				return ctx.Err()
+			}
+			last = fork
+		}
+
+		head, err := w.backend.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("reading head: %w", err)
+		}
+		if head.Number.Uint64() <= last {
+			continue
+		}
+
+		from := new(big.Int).SetUint64(last + 1)
+		logs, err := w.backend.FilterLogs(ctx, w.query(from, head.Number))
+		if err != nil {
+			return fmt.Errorf("filtering Sync logs: %w", err)
+		}
+
+		// Group the logs by block; within a block the last Sync per pool wins
+		batches := make(map[uint64]*Batch)
+		for _, log := range logs {
+			reserves, err := w.parseSync(log)
+			if err != nil {
+				continue
+			}
+			batch, ok := batches[log.BlockNumber]
+			if !ok {
+				batch = newBatch(log.BlockNumber)
+				batches[log.BlockNumber] = batch
+			}
+			batch.Reserves[log.Address] = reserves
+			hashes[log.BlockNumber] = log.BlockHash
+			touched[log.BlockNumber] = append(touched[log.BlockNumber], log.Address)
+		}
+
+		blocks := make([]uint64, 0, len(batches))
+		for blockNumber := range batches {
+			blocks = append(blocks, blockNumber)
+		}
+		slices.Sort(blocks)
+		for _, blockNumber := range blocks {
+			if !send(ctx, out, batches[blockNumber]) {
+				return ctx.Err()
+			}
+		}
+
+		last = head.Number.Uint64()
+		hashes[last] = head.Hash()
+
+		// Forget blocks that are too old to be reorged
+		for blockNumber := range hashes {
+			if blockNumber+reorgWindow < last {
+				delete(hashes, blockNumber)
+				delete(touched, blockNumber)
+			}
+		}
+	}
+}
+
+// findFork checks the remembered block hashes from newest to oldest and returns
+// the newest block that is still canonical, and whether anything was reorged
+func (w *SyncWatcher) findFork(ctx context.Context, last uint64, hashes map[uint64]common.Hash) (uint64, bool, error) {
+	blocks := make([]uint64, 0, len(hashes))
+	for blockNumber := range hashes {
+		blocks = append(blocks, blockNumber)
+	}
+	slices.Sort(blocks)
+	slices.Reverse(blocks)
+
+	for i, blockNumber := range blocks {
+		header, err := w.backend.HeaderByNumber(ctx, new(big.Int).SetUint64(blockNumber))
+		if err != nil {
+			return 0, false, fmt.Errorf("reading block %d: %w", blockNumber, err)
+		}
+		if header.Hash() == hashes[blockNumber] {
+			return blockNumber, i > 0, nil
+		}
+	}
+
+	// Nothing in the window is canonical any more
+	if len(blocks) == 0 {
+		return last, false, nil
+	}
+
+	return blocks[len(blocks)-1] - 1, true, nil
+}
+
+// reread fetches the current reserves of pools whose Sync logs were removed
+func (w *SyncWatcher) reread(ctx context.Context, pools []common.Address) *Batch {
+	batch, err := w.reader.ReservesBatch(ctx, pools)
+	if err != nil {
+		batch = newBatch(0)
+		for _, pool := range pools {
+			batch.Errors[pool] = err
+		}
+	}
+	batch.Reorg = true
+
+	return batch
+}
+
+// query builds the Sync log filter for the watched pools
+func (w *SyncWatcher) query(from, to *big.Int) ethereum.FilterQuery {
+	return ethereum.FilterQuery{
+		FromBlock: from,
+		ToBlock:   to,
+		Addresses: w.pools,
+		Topics:    [][]common.Hash{{w.syncID}},
+	}
+}
+
+// parseSync decodes the reserves carried by a Sync log
+func (w *SyncWatcher) parseSync(log types.Log) (*Reserves, error) {
+	filterer, err := uniswapv2.NewIUniswapV2PairFilterer(log.Address, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	event, err := filterer.ParseSync(log)
+	if err != nil {
+		return nil, err
+	}
+
+	// Sync does not carry blockTimestampLast, so Timestamp stays zero
+	return &Reserves{Pool: log.Address, Reserve0: event.Reserve0, Reserve1: event.Reserve1}, nil
+}
+
+// newBatch creates an empty batch for a block
+func newBatch(blockNumber uint64) *Batch {
+	return &Batch{
+		BlockNumber: blockNumber,
+		Reserves:    make(map[common.Address]*Reserves),
+		Errors:      make(map[common.Address]error),
+	}
+}
+
+// send delivers a batch unless ctx is cancelled first
+func send(ctx context.Context, out chan<- *Batch, batch *Batch) bool {
+	select {
+	case out <- batch:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
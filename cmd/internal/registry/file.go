@@ -0,0 +1,344 @@
+package registry
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/constants"
+	"github.com/ethereum/go-ethereum/common"
+	"gopkg.in/yaml.v3"
+)
+
+// File is the content of a pool registry file, in YAML or, with a .json extension, JSON:
+//
+//	include_builtin: true   # merge with the built-in pools (default); false to replace them
+//	pools:
+//	  - name: eUSD_eEUR_Pool
+//	    address: "0x7CD9e0a93cAc1729369C5D428daED0b1f9d07Fe7"
+//	    dex: UniswapV2          # optional, default UniswapV2
+//	    fee_bps: 30             # optional, default the DEX's fee
+//	    token0: {symbol: eUSD, address: "0x…", decimals: 6}
+//	    token1: {symbol: eEUR, address: "0x…", decimals: 6}
+//	    target_ratio: 1.087     # optional, whole token0 per token1: 1 eEUR = 1.087 eUSD
+//
+// The target ratio is the reserve0/reserve1 price in whole tokens, token0 per token1.
+// Addresses must be EIP-55 checksummed so a mistyped character is caught.
+type File struct {
+	IncludeBuiltin *bool      `yaml:"include_builtin,omitempty" json:"include_builtin,omitempty"`
+	Pools          []FilePool `yaml:"pools" json:"pools"`
+}
+
+// FilePool is a pool as written in a registry file
+type FilePool struct {
+	Name        string    `yaml:"name" json:"name"`
+	Address     string    `yaml:"address" json:"address"`
+	DEX         string    `yaml:"dex,omitempty" json:"dex,omitempty"`
+	FeeBps      *uint64   `yaml:"fee_bps,omitempty" json:"fee_bps,omitempty"`
+	Token0      FileToken `yaml:"token0" json:"token0"`
+	Token1      FileToken `yaml:"token1" json:"token1"`
+	TargetRatio *float64  `yaml:"target_ratio,omitempty" json:"target_ratio,omitempty"`
+}
+
+// FileToken is a pool token as written in a registry file. Only the symbol is required.
+type FileToken struct {
+	Symbol   string `yaml:"symbol" json:"symbol"`
+	Address  string `yaml:"address,omitempty" json:"address,omitempty"`
+	Decimals *uint8 `yaml:"decimals,omitempty" json:"decimals,omitempty"`
+}
+
+// Load returns the built-in registry merged with the registry file at path.
+// An empty path gives the built-in pools alone.
+func Load(path string) (*Registry, error) {
+	if path == "" {
+		return Builtin(), nil
+	}
+
+	file, err := ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return file.Registry(path)
+}
+
+// Registry returns the registry the file describes, with source as the Source of its pools
+func (f *File) Registry(source string) (*Registry, error) {
+	r := New()
+	if f.IncludeBuiltin == nil || *f.IncludeBuiltin {
+		r = Builtin()
+	}
+
+	if err := f.mergeInto(r, source); err != nil {
+		return nil, fmt.Errorf("%s: %w", source, err)
+	}
+
+	return r, nil
+}
+
+// WriteFile validates the file and writes it to path, as JSON when path has a
+// .json extension and YAML otherwise. The previous content is replaced
+// atomically, so a failed write leaves it intact. Comments are not preserved.
+func WriteFile(path string, f *File) error {
+	if _, err := f.Registry(path); err != nil {
+		return err
+	}
+
+	var data []byte
+	var err error
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		data, err = json.MarshalIndent(f, "", "  ")
+		data = append(data, '\n')
+	} else {
+		var buf bytes.Buffer
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		err = encoder.Encode(f)
+		data = buf.Bytes()
+	}
+	if err != nil {
+		return fmt.Errorf("encoding pool registry: %w", err)
+	}
+
+	// Keep the permissions of an existing file
+	mode := os.FileMode(0o644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("writing pool registry: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing pool registry: %w", err)
+	}
+	if err := tmp.Chmod(mode); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing pool registry: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing pool registry: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("writing pool registry: %w", err)
+	}
+
+	return nil
+}
+
+// NewFilePool converts a pool for writing to a registry file. The fee is left
+// out when it is the DEX's default and unknown token fields are omitted.
+func NewFilePool(pool *Pool) FilePool {
+	filePool := FilePool{
+		Name:    pool.Name,
+		Address: pool.Address.Hex(),
+		DEX:     pool.DEX,
+		Token0:  newFileToken(pool.Token0),
+		Token1:  newFileToken(pool.Token1),
+	}
+
+	if fee, ok := constants.DEXFeeBps[pool.DEX]; !ok || fee != pool.FeeBps {
+		feeBps := pool.FeeBps
+		filePool.FeeBps = &feeBps
+	}
+	if pool.HasTarget() {
+		targetRatio := pool.TargetRatio
+		filePool.TargetRatio = &targetRatio
+	}
+
+	return filePool
+}
+
+// newFileToken converts a pool token for writing to a registry file
+func newFileToken(token Token) FileToken {
+	fileToken := FileToken{Symbol: token.Symbol}
+	if token.Address != (common.Address{}) {
+		fileToken.Address = token.Address.Hex()
+	}
+	if token.Decimals != 0 {
+		decimals := token.Decimals
+		fileToken.Decimals = &decimals
+	}
+
+	return fileToken
+}
+
+// ReadFile parses a registry file. Unknown keys are rejected so a misspelled
+// field is not silently ignored.
+func ReadFile(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading pool registry: %w", err)
+	}
+
+	file := &File{}
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(file)
+	} else {
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		err = decoder.Decode(file)
+	}
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parsing pool registry %s: %w", path, err)
+	}
+
+	return file, nil
+}
+
+// mergeInto validates every pool of the file and adds it to r, replacing
+// registered pools of the same name
+func (f *File) mergeInto(r *Registry, source string) error {
+	pools := make([]*Pool, 0, len(f.Pools))
+	names := make(map[string]bool, len(f.Pools))
+	addresses := make(map[common.Address]string, len(f.Pools))
+
+	for i, filePool := range f.Pools {
+		pool, err := filePool.validate(source)
+		if err != nil {
+			if filePool.Name != "" {
+				return fmt.Errorf("pool %s: %w", filePool.Name, err)
+			}
+			return fmt.Errorf("pool #%d: %w", i+1, err)
+		}
+
+		if names[pool.Name] {
+			return fmt.Errorf("pool %s is listed twice", pool.Name)
+		}
+		if other, ok := addresses[pool.Address]; ok {
+			return fmt.Errorf("pools %s and %s have the same address %s", other, pool.Name, pool.Address.Hex())
+		}
+		names[pool.Name] = true
+		addresses[pool.Address] = pool.Name
+		pools = append(pools, pool)
+	}
+
+	// Validate everything before changing the registry, then replace overridden
+	// pools first so two file pools can swap the addresses of built-in ones
+	for _, pool := range pools {
+		if existing, ok := r.Pool(pool.Name); ok {
+			delete(r.addresses, existing.Address)
+			delete(r.pools, existing.Name)
+		}
+	}
+	for _, pool := range pools {
+		if err := r.Add(pool); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validate checks a file pool and fills in its defaults
+func (p FilePool) validate(source string) (*Pool, error) {
+	if p.Name == "" {
+		return nil, errors.New("name is required")
+	}
+
+	address, err := checksummed(p.Address)
+	if err != nil {
+		return nil, fmt.Errorf("address: %w", err)
+	}
+
+	pool := &Pool{
+		Name:    p.Name,
+		Address: address,
+		DEX:     p.DEX,
+		Source:  source,
+	}
+	if pool.DEX == "" {
+		pool.DEX = constants.DefaultDEX
+	}
+
+	if p.FeeBps != nil {
+		pool.FeeBps = *p.FeeBps
+	} else if fee, ok := constants.DEXFeeBps[pool.DEX]; ok {
+		pool.FeeBps = fee
+	} else {
+		return nil, fmt.Errorf("fee_bps is required for DEX %s, which has no default fee", pool.DEX)
+	}
+	if pool.FeeBps >= 10_000 {
+		return nil, fmt.Errorf("fee_bps %d is not below 10000", pool.FeeBps)
+	}
+
+	if pool.Token0, err = p.Token0.validate(); err != nil {
+		return nil, fmt.Errorf("token0: %w", err)
+	}
+	if pool.Token1, err = p.Token1.validate(); err != nil {
+		return nil, fmt.Errorf("token1: %w", err)
+	}
+	if strings.EqualFold(pool.Token0.Symbol, pool.Token1.Symbol) {
+		return nil, fmt.Errorf("token0 and token1 are both %s", pool.Token0.Symbol)
+	}
+	if pool.Token0.Address != (common.Address{}) && pool.Token0.Address == pool.Token1.Address {
+		return nil, fmt.Errorf("token0 and token1 have the same address %s", pool.Token0.Address.Hex())
+	}
+
+	if p.TargetRatio != nil {
+		if *p.TargetRatio <= 0 {
+			return nil, fmt.Errorf("target_ratio must be positive, got %g", *p.TargetRatio)
+		}
+		pool.TargetRatio = *p.TargetRatio
+	}
+
+	return pool, nil
+}
+
+// Token validates a file token and returns it
+func (t FileToken) Token() (Token, error) {
+	return t.validate()
+}
+
+// validate checks a file token
+func (t FileToken) validate() (Token, error) {
+	if t.Symbol == "" {
+		return Token{}, errors.New("symbol is required")
+	}
+
+	token := Token{Symbol: t.Symbol}
+	if t.Address != "" {
+		address, err := checksummed(t.Address)
+		if err != nil {
+			return Token{}, fmt.Errorf("address: %w", err)
+		}
+		token.Address = address
+	}
+	if t.Decimals != nil {
+		if *t.Decimals == 0 || *t.Decimals > 36 {
+			return Token{}, fmt.Errorf("decimals must be between 1 and 36, got %d", *t.Decimals)
+		}
+		token.Decimals = *t.Decimals
+	}
+
+	return token, nil
+}
+
+// checksummed parses an address that must be written with its EIP-55 checksum
+func checksummed(address string) (common.Address, error) {
+	if address == "" {
+		return common.Address{}, errors.New("is required")
+	}
+	if !common.IsHexAddress(address) {
+		return common.Address{}, fmt.Errorf("%q is not an address", address)
+	}
+
+	parsed := common.HexToAddress(address)
+	if parsed.Hex() != address {
+		return common.Address{}, fmt.Errorf("%s is not checksummed, expected %s", address, parsed.Hex())
+	}
+
+	return parsed, nil
+}
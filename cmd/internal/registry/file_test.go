@@ -0,0 +1,134 @@
+package registry
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/constants"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Checksummed addresses for the test registries
+var (
+	poolA  = common.HexToAddress("0x00000000000000000000000000000000000a11ce").Hex()
+	poolB  = common.HexToAddress("0x0000000000000000000000000000000000000b0b").Hex()
+	tokenX = common.HexToAddress("0xabcdefabcdefabcdefabcdefabcdefabcdefabcd").Hex()
+)
+
+// writeRegistry writes a registry file with the given extension and returns its path
+func writeRegistry(t *testing.T, ext, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "pools"+ext)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadMergesWithBuiltin(t *testing.T) {
+	path := writeRegistry(t, ".yaml", `
+pools:
+  - name: eUSD_eEUR_Pool
+    address: "`+poolA+`"
+    token0: {symbol: eUSD, address: "`+tokenX+`", decimals: 6}
+    token1: {symbol: eEUR}
+    target_ratio: 1.1
+  - name: eGBP_eJPY_Pool
+    address: "`+poolB+`"
+    fee_bps: 25
+    token0: {symbol: eGBP}
+    token1: {symbol: eJPY}
+`)
+	r, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := len(constants.UniV2Pools) + 1; r.Len() != want {
+		t.Errorf("registry has %d pools, want %d", r.Len(), want)
+	}
+
+	// The file replaces the built-in pool of the same name, address included
+	overridden, ok := r.Pool("eUSD_eEUR_Pool")
+	if !ok || overridden.Address.Hex() != poolA || overridden.Source != path || overridden.TargetRatio != 1.1 {
+		t.Fatalf("eUSD_eEUR_Pool = %+v", overridden)
+	}
+	if overridden.FeeBps != constants.DEXFeeBps[constants.DefaultDEX] || overridden.DEX != constants.DefaultDEX {
+		t.Errorf("defaults not applied: DEX %s, fee %d", overridden.DEX, overridden.FeeBps)
+	}
+	if overridden.Token0.Address.Hex() != tokenX || overridden.Token0.Decimals != 6 || overridden.Token1.Decimals != 0 {
+		t.Errorf("tokens = %+v, %+v", overridden.Token0, overridden.Token1)
+	}
+	if _, ok := r.ByAddress(common.HexToAddress(constants.UniV2Pools["eUSD_eEUR_Pool"])); ok {
+		t.Error("the replaced built-in address is still registered")
+	}
+
+	added, ok := r.Lookup(poolB)
+	if !ok || added.Name != "eGBP_eJPY_Pool" || added.FeeBps != 25 || added.HasTarget() {
+		t.Errorf("eGBP_eJPY_Pool = %+v", added)
+	}
+}
+
+func TestLoadWithoutBuiltin(t *testing.T) {
+	path := writeRegistry(t, ".json", `{
+  "include_builtin": false,
+  "pools": [{"name": "eGBP_eJPY_Pool", "address": "`+poolB+`", "token0": {"symbol": "eGBP"}, "token1": {"symbol": "eJPY"}}]
+}`)
+	r, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := r.Names(); len(names) != 1 || names[0] != "eGBP_eJPY_Pool" {
+		t.Errorf("pools = %v, want only eGBP_eJPY_Pool", names)
+	}
+
+	builtin, err := Load("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if builtin.Len() != len(constants.UniV2Pools) {
+		t.Errorf("Load(\"\") gave %d pools, want the %d built-in ones", builtin.Len(), len(constants.UniV2Pools))
+	}
+}
+
+func TestLoadRejectsBadRegistries(t *testing.T) {
+	lower := strings.ToLower(poolA)
+	pool := func(name, address, extra string) string {
+		return "  - name: " + name + "\n    address: \"" + address + "\"\n" +
+			"    token0: {symbol: eGBP}\n    token1: {symbol: eJPY}\n" + extra
+	}
+	builtinAddress := common.HexToAddress(constants.UniV2Pools["eUSD_eEUR_Pool"]).Hex()
+
+	tests := []struct {
+		name  string
+		pools string
+		want  string
+	}{
+		{"unchecksummed pool address", pool("P", lower, ""), "not checksummed"},
+		{"unchecksummed token address", "  - name: P\n    address: \"" + poolA + "\"\n    token0: {symbol: eGBP, address: \"" + strings.ToLower(tokenX) + "\"}\n    token1: {symbol: eJPY}\n", "not checksummed"},
+		{"not an address", pool("P", "0x1234", ""), "not an address"},
+		{"missing address", "  - name: P\n    token0: {symbol: eGBP}\n    token1: {symbol: eJPY}\n", "address: is required"},
+		{"duplicate name", pool("P", poolA, "") + pool("P", poolB, ""), "listed twice"},
+		{"duplicate address", pool("P", poolA, "") + pool("Q", poolA, ""), "same address"},
+		{"address of a built-in pool", pool("P", builtinAddress, ""), "has the address of built-in pool eUSD_eEUR_Pool"},
+		{"same symbols", "  - name: P\n    address: \"" + poolA + "\"\n    token0: {symbol: eGBP}\n    token1: {symbol: egbp}\n", "both"},
+		{"missing symbol", "  - name: P\n    address: \"" + poolA + "\"\n    token0: {decimals: 6}\n    token1: {symbol: eJPY}\n", "symbol is required"},
+		{"zero decimals", "  - name: P\n    address: \"" + poolA + "\"\n    token0: {symbol: eGBP, decimals: 0}\n    token1: {symbol: eJPY}\n", "decimals"},
+		{"negative target", pool("P", poolA, "    target_ratio: -1\n"), "target_ratio"},
+		{"whole fee", pool("P", poolA, "    fee_bps: 10000\n"), "fee_bps"},
+		{"unknown DEX without fee", pool("P", poolA, "    dex: Elsewhere\n"), "fee_bps is required"},
+		{"misspelled field", pool("P", poolA, "    target_ration: 1\n"), "target_ration"},
+	}
+	for _, test := range tests {
+		_, err := Load(writeRegistry(t, ".yml", "pools:\n"+test.pools))
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: error = %v, want one mentioning %q", test.name, err, test.want)
+		}
+	}
+
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("a missing registry file was accepted")
+	}
+}
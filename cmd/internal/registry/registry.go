@@ -0,0 +1,195 @@
+// Package registry is the set of pools the commands trade on: the built-in
+// defaults from the constants package, merged with an optional pool registry
+// file so pools can be added or networks switched without a rebuild.
+package registry
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/constants"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// BuiltinSource is the Source of pools compiled into the binary
+const BuiltinSource = "built-in"
+
+// Token is one side of a pool as far as the registry knows it. A zero Address or
+// Decimals means the registry does not know it and it has to be read on-chain.
+type Token struct {
+	Symbol   string
+	Address  common.Address
+	Decimals uint8
+}
+
+// Pool is a registered pool
+type Pool struct {
+	Name        string
+	Address     common.Address
+	DEX         string
+	FeeBps      uint64
+	Token0      Token
+	Token1      Token
+	TargetRatio float64 // whole token0 per token1 (reserve0/reserve1) the pool should trade at, 0 when unset
+	Source      string  // BuiltinSource or the registry file the pool came from
+}
+
+// HasTarget reports whether the pool has a target ratio to be compared against
+func (p *Pool) HasTarget() bool {
+	return p.TargetRatio > 0
+}
+
+// Orient sets the pool's tokens to its on-chain token0 and token1. When the
+// registered tokens are those two the other way round, as a pool name can give
+// them, the target ratio is inverted so that it stays token0 per token1.
+func (p *Pool) Orient(token0, token1 Token) {
+	if p.reversed(token0, token1) && p.HasTarget() {
+		p.TargetRatio = 1 / p.TargetRatio
+	}
+
+	p.Token0, p.Token1 = token0, token1
+}
+
+// reversed reports whether the registered tokens are token1 and token0 rather than token0 and token1
+func (p *Pool) reversed(token0, token1 Token) bool {
+	if p.Token0.matches(token0) && p.Token1.matches(token1) {
+		return false
+	}
+
+	return p.Token0.matches(token1) && p.Token1.matches(token0)
+}
+
+// matches reports whether a registered token is the given one: by address when
+// both are known, by symbol otherwise
+func (t Token) matches(other Token) bool {
+	if t.Address != (common.Address{}) && other.Address != (common.Address{}) {
+		return t.Address == other.Address
+	}
+
+	return strings.EqualFold(t.Symbol, other.Symbol)
+}
+
+// Registry is a set of pools indexed by name and address
+type Registry struct {
+	pools     map[string]*Pool
+	addresses map[common.Address]*Pool
+}
+
+// New returns an empty registry
+func New() *Registry {
+	return &Registry{
+		pools:     make(map[string]*Pool),
+		addresses: make(map[common.Address]*Pool),
+	}
+}
+
+// Builtin returns the registry of the pools in constants.UniV2Pools. Their token
+// symbols come from the pool names; token addresses and decimals are not known,
+// so the order of the name is only assumed to be the on-chain token0 and token1
+// until Orient is given the pool's real tokens.
+func Builtin() *Registry {
+	r := New()
+
+	for poolName, poolAddress := range constants.UniV2Pools {
+		dex, ok := constants.PoolDEX[poolName]
+		if !ok {
+			dex = constants.DefaultDEX
+		}
+		symbol0, symbol1 := symbolsFromPoolName(poolName)
+
+		r.put(&Pool{
+			Name:        poolName,
+			Address:     common.HexToAddress(poolAddress),
+			DEX:         dex,
+			FeeBps:      constants.FeeBps(poolName),
+			Token0:      Token{Symbol: symbol0},
+			Token1:      Token{Symbol: symbol1},
+			TargetRatio: constants.TargetRatios[poolName],
+			Source:      BuiltinSource,
+		})
+	}
+
+	return r
+}
+
+// Add registers a pool. A pool with the same name replaces the existing one; a
+// pool reusing the address of a pool with another name is refused.
+func (r *Registry) Add(pool *Pool) error {
+	if existing, ok := r.addresses[pool.Address]; ok && existing.Name != pool.Name {
+		return fmt.Errorf("pool %s has the address of %s pool %s (%s)", pool.Name, existing.Source, existing.Name, pool.Address.Hex())
+	}
+
+	if existing, ok := r.pools[pool.Name]; ok {
+		delete(r.addresses, existing.Address)
+	}
+	r.put(pool)
+
+	return nil
+}
+
+// put indexes a pool without any checks
+func (r *Registry) put(pool *Pool) {
+	r.pools[pool.Name] = pool
+	r.addresses[pool.Address] = pool
+}
+
+// Pool returns the pool with the given name
+func (r *Registry) Pool(name string) (*Pool, bool) {
+	pool, ok := r.pools[name]
+	return pool, ok
+}
+
+// ByAddress returns the pool at the given address
+func (r *Registry) ByAddress(address common.Address) (*Pool, bool) {
+	pool, ok := r.addresses[address]
+	return pool, ok
+}
+
+// Lookup returns the pool with the given name or address
+func (r *Registry) Lookup(nameOrAddress string) (*Pool, bool) {
+	if common.IsHexAddress(nameOrAddress) {
+		return r.ByAddress(common.HexToAddress(nameOrAddress))
+	}
+
+	return r.Pool(nameOrAddress)
+}
+
+// Names returns the names of all pools in alphabetical order, so every command
+// iterates the registry the same way
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.pools))
+	for name := range r.pools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Pools returns all pools ordered by name
+func (r *Registry) Pools() []*Pool {
+	names := r.Names()
+
+	pools := make([]*Pool, len(names))
+	for i, name := range names {
+		pools[i] = r.pools[name]
+	}
+
+	return pools
+}
+
+// Len returns the number of pools
+func (r *Registry) Len() int {
+	return len(r.pools)
+}
+
+// symbolsFromPoolName extracts the token symbols from a name like "eEUR_eAUD_Pool"
+func symbolsFromPoolName(poolName string) (string, string) {
+	parts := strings.Split(poolName, "_")
+	if len(parts) < 2 {
+		return "TokenA", "TokenB"
+	}
+
+	return parts[0], parts[1]
+}
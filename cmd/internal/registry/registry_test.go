@@ -0,0 +1,92 @@
+package registry
+
+import (
+	"testing"
+
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/constants"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestBuiltin(t *testing.T) {
+	r := Builtin()
+	if r.Len() != len(constants.UniV2Pools) {
+		t.Fatalf("builtin registry has %d pools, want %d", r.Len(), len(constants.UniV2Pools))
+	}
+
+	pool, ok := r.Lookup("eUSD_eEUR_Pool")
+	if !ok {
+		t.Fatal("eUSD_eEUR_Pool is not registered")
+	}
+	if pool.Token0.Symbol != "eUSD" || pool.Token1.Symbol != "eEUR" || pool.Source != BuiltinSource {
+		t.Errorf("eUSD_eEUR_Pool = %+v", pool)
+	}
+	if pool.TargetRatio != constants.TargetRatios["eUSD_eEUR_Pool"] {
+		t.Errorf("target ratio = %v, want %v", pool.TargetRatio, constants.TargetRatios["eUSD_eEUR_Pool"])
+	}
+	if byAddress, ok := r.Lookup(constants.UniV2Pools["eUSD_eEUR_Pool"]); !ok || byAddress != pool {
+		t.Error("lookup by address does not find the pool")
+	}
+
+	names := r.Names()
+	for i := 1; i < len(names); i++ {
+		if names[i-1] >= names[i] {
+			t.Errorf("names are not sorted: %v", names)
+		}
+	}
+}
+
+func TestAdd(t *testing.T) {
+	r := New()
+	address := common.HexToAddress("0x0a")
+	if err := r.Add(&Pool{Name: "A", Address: address}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Add(&Pool{Name: "B", Address: address}); err == nil {
+		t.Error("a second pool at the same address was added")
+	}
+
+	// Replacing a pool by name frees its old address
+	if err := r.Add(&Pool{Name: "A", Address: common.HexToAddress("0x0b")}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := r.ByAddress(address); ok {
+		t.Error("the replaced address is still registered")
+	}
+	if err := r.Add(&Pool{Name: "B", Address: address}); err != nil {
+		t.Errorf("adding at the freed address: %v", err)
+	}
+}
+
+func TestOrient(t *testing.T) {
+	usd := Token{Symbol: "eUSD", Address: common.HexToAddress("0x01"), Decimals: 6}
+	eur := Token{Symbol: "eEUR", Address: common.HexToAddress("0x02"), Decimals: 18}
+
+	tests := []struct {
+		name           string
+		token0, token1 Token // as registered
+		target         float64
+		want           float64
+	}{
+		{"same order by symbol", Token{Symbol: "eusd"}, Token{Symbol: "eEUR"}, 1.087, 1.087},
+		{"reversed by symbol", Token{Symbol: "eEUR"}, Token{Symbol: "eUSD"}, 0.92, 1 / 0.92},
+		{"reversed by address", Token{Symbol: "X", Address: eur.Address}, Token{Symbol: "Y", Address: usd.Address}, 0.5, 2},
+		{"reversed without target", Token{Symbol: "eEUR"}, Token{Symbol: "eUSD"}, 0, 0},
+		{"unrelated tokens", Token{Symbol: "eGBP"}, Token{Symbol: "eJPY"}, 1.2, 1.2},
+	}
+	for _, test := range tests {
+		pool := &Pool{Name: test.name, Token0: test.token0, Token1: test.token1, TargetRatio: test.target}
+		pool.Orient(usd, eur)
+		if pool.Token0 != usd || pool.Token1 != eur {
+			t.Errorf("%s: tokens = %+v, %+v", test.name, pool.Token0, pool.Token1)
+		}
+		if pool.TargetRatio != test.want {
+			t.Errorf("%s: target ratio = %v, want %v", test.name, pool.TargetRatio, test.want)
+		}
+
+		// Orienting again with the same tokens changes nothing
+		pool.Orient(usd, eur)
+		if pool.TargetRatio != test.want {
+			t.Errorf("%s: target ratio after a second Orient = %v, want %v", test.name, pool.TargetRatio, test.want)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package signer
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/external"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// External delegates signing to an external signer speaking Clef's account_*
+// JSON-RPC API. Transactions are still built by the bot; only the signature
+// comes from the signer, so the private key never has to be on this machine.
+type External struct {
+	endpoint string
+	address  common.Address
+	client   *external.ExternalSigner
+}
+
+// NewExternal connects to the signer at endpoint and checks it manages address
+func NewExternal(endpoint string, address common.Address) (*External, error) {
+	client, err := external.NewExternalSigner(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to signer at %s: %w", endpoint, err)
+	}
+
+	// Clef may ask its operator to approve listing accounts, so this can block until answered
+	if !client.Contains(accounts.Account{Address: address}) {
+		return nil, fmt.Errorf("signer at %s does not list account %s", endpoint, address.Hex())
+	}
+
+	return &External{endpoint: endpoint, address: address, client: client}, nil
+}
+
+// Address returns the account the signer signs for
+func (e *External) Address() common.Address {
+	return e.address
+}
+
+// TransactOpts sends every transaction to the external signer and checks the
+// signed transaction is the one that was asked for, from the expected account
+func (e *External) TransactOpts(ctx context.Context, chainID *big.Int) (*bind.TransactOpts, error) {
+	latest := types.LatestSignerForChainID(chainID)
+
+	return &bind.TransactOpts{
+		From:    e.address,
+		Context: ctx,
+		Signer: func(address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			if address != e.address {
+				return nil, bind.ErrNotAuthorized
+			}
+
+			signed, err := e.client.SignTx(accounts.Account{Address: address}, tx, chainID)
+			if err != nil {
+				return nil, fmt.Errorf("signer at %s: %w", e.endpoint, err)
+			}
+
+			// The signer returns the whole transaction: make sure it did not change any field
+			if latest.Hash(signed) != latest.Hash(tx) {
+				return nil, fmt.Errorf("signer at %s returned a different transaction than requested", e.endpoint)
+			}
+			sender, err := types.Sender(latest, signed)
+			if err != nil {
+				return nil, fmt.Errorf("signer at %s returned an invalid signature: %w", e.endpoint, err)
+			}
+			if sender != address {
+				return nil, fmt.Errorf("signer at %s signed as %s, not %s", e.endpoint, sender.Hex(), address.Hex())
+			}
+
+			return signed, nil
+		},
+	}, nil
+}
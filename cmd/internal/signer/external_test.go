@@ -0,0 +1,214 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+var testChainID = big.NewInt(1337)
+
+// newTestKeystore returns a keystore signer for a fresh key
+func newTestKeystore(t *testing.T) *Keystore {
+	t.Helper()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &Keystore{path: "test", address: crypto.PubkeyToAddress(key.PublicKey), key: key}
+}
+
+// serveMock runs ServeMock for the keystore on a loopback port until the test ends
+func serveMock(t *testing.T, signer *Keystore, requests chan<- MockRequest) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- ServeMock(listener, signer, requests) }()
+	t.Cleanup(func() {
+		listener.Close()
+		if err := <-done; err != nil {
+			t.Errorf("mock signer: %v", err)
+		}
+	})
+
+	return "http://" + listener.Addr().String()
+}
+
+// serveAPI serves api as the account namespace until the test ends
+func serveAPI(t *testing.T, api interface{}) string {
+	t.Helper()
+
+	server := rpc.NewServer()
+	if err := server.RegisterName("account", api); err != nil {
+		t.Fatal(err)
+	}
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(func() {
+		httpServer.Close()
+		server.Stop()
+	})
+
+	return httpServer.URL
+}
+
+// testTx is an unsigned transfer on the test chain
+func testTx() *types.Transaction {
+	to := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	return types.NewTx(&types.DynamicFeeTx{
+		ChainID:   testChainID,
+		Nonce:     3,
+		GasTipCap: big.NewInt(1_000_000_000),
+		GasFeeCap: big.NewInt(30_000_000_000),
+		Gas:       21_000,
+		To:        &to,
+		Value:     big.NewInt(12345),
+	})
+}
+
+// signWith opens an External signer for address at endpoint and signs testTx
+func signWith(t *testing.T, endpoint string, address common.Address) (*types.Transaction, error) {
+	t.Helper()
+
+	signer, err := NewExternal(endpoint, address)
+	if err != nil {
+		t.Fatalf("NewExternal: %v", err)
+	}
+	opts, err := signer.TransactOpts(context.Background(), testChainID)
+	if err != nil {
+		t.Fatalf("TransactOpts: %v", err)
+	}
+	if opts.From != address {
+		t.Fatalf("opts.From = %s, want %s", opts.From.Hex(), address.Hex())
+	}
+
+	return opts.Signer(address, testTx())
+}
+
+func TestExternalSignsThroughMock(t *testing.T) {
+	account := newTestKeystore(t)
+	requests := make(chan MockRequest, 1)
+	endpoint := serveMock(t, account, requests)
+
+	tx := testTx()
+	signed, err := signWith(t, endpoint, account.Address())
+	if err != nil {
+		t.Fatalf("signing: %v", err)
+	}
+
+	latest := types.LatestSignerForChainID(testChainID)
+	if latest.Hash(signed) != latest.Hash(tx) {
+		t.Errorf("signed transaction hashes to %s, want %s", latest.Hash(signed).Hex(), latest.Hash(tx).Hex())
+	}
+	sender, err := types.Sender(latest, signed)
+	if err != nil {
+		t.Fatalf("recovering sender: %v", err)
+	}
+	if sender != account.Address() {
+		t.Errorf("sender = %s, want %s", sender.Hex(), account.Address().Hex())
+	}
+
+	request := <-requests
+	if request.Err != nil {
+		t.Fatalf("mock refused the request: %v", request.Err)
+	}
+	if request.Tx.Hash() != signed.Hash() {
+		t.Errorf("mock signed %s, External returned %s", request.Tx.Hash().Hex(), signed.Hash().Hex())
+	}
+}
+
+func TestExternalRefusesOtherAccounts(t *testing.T) {
+	account := newTestKeystore(t)
+	endpoint := serveMock(t, account, nil)
+
+	other := newTestKeystore(t).Address()
+	if _, err := NewExternal(endpoint, other); err == nil || !strings.Contains(err.Error(), "does not list account") {
+		t.Errorf("NewExternal for an unlisted account: err = %v", err)
+	}
+
+	signer, err := NewExternal(endpoint, account.Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts, err := signer.TransactOpts(context.Background(), testChainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := opts.Signer(other, testTx()); !errors.Is(err, bind.ErrNotAuthorized) {
+		t.Errorf("signing for another account: err = %v, want %v", err, bind.ErrNotAuthorized)
+	}
+}
+
+// tamperingAPI is a mock signer that changes the transaction before signing it
+type tamperingAPI struct {
+	mockAPI
+}
+
+func (a *tamperingAPI) SignTransaction(ctx context.Context, args apitypes.SendTxArgs) (*signTransactionResult, error) {
+	args.Value = hexutil.Big(*new(big.Int).Add(args.Value.ToInt(), big.NewInt(1)))
+	return a.mockAPI.SignTransaction(ctx, args)
+}
+
+func TestExternalRejectsTamperedTransaction(t *testing.T) {
+	account := newTestKeystore(t)
+	endpoint := serveAPI(t, &tamperingAPI{mockAPI{signer: account}})
+
+	_, err := signWith(t, endpoint, account.Address())
+	if err == nil || !strings.Contains(err.Error(), "returned a different transaction") {
+		t.Errorf("err = %v, want a different transaction error", err)
+	}
+}
+
+// impostorAPI lists one account but signs with another key
+type impostorAPI struct {
+	mockAPI
+	listed common.Address
+}
+
+func (a *impostorAPI) List() []common.Address {
+	return []common.Address{a.listed}
+}
+
+func (a *impostorAPI) SignTransaction(ctx context.Context, args apitypes.SendTxArgs) (*signTransactionResult, error) {
+	args.From = common.NewMixedcaseAddress(a.signer.Address())
+	return a.mockAPI.SignTransaction(ctx, args)
+}
+
+func TestExternalRejectsWrongSender(t *testing.T) {
+	account := newTestKeystore(t)
+	endpoint := serveAPI(t, &impostorAPI{mockAPI: mockAPI{signer: newTestKeystore(t)}, listed: account.Address()})
+
+	_, err := signWith(t, endpoint, account.Address())
+	if err == nil || !strings.Contains(err.Error(), "signed as") {
+		t.Errorf("err = %v, want a wrong sender error", err)
+	}
+}
+
+func TestServeMockRefusesPublicAddresses(t *testing.T) {
+	listener, err := net.Listen("tcp", "0.0.0.0:0")
+	if err != nil {
+		t.Skipf("cannot listen on all interfaces: %v", err)
+	}
+	defer listener.Close()
+
+	if err := ServeMock(listener, newTestKeystore(t), nil); err == nil {
+		t.Error("ServeMock served on a non-loopback address")
+	}
+}
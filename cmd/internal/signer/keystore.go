@@ -0,0 +1,68 @@
+package signer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Keystore signs with a private key decrypted from a keystore JSON file
+type Keystore struct {
+	path    string
+	address common.Address
+	key     *ecdsa.PrivateKey
+}
+
+// NewKeystore decrypts the keystore file at path with the password
+func NewKeystore(path, password string) (*Keystore, error) {
+	keyJSON, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading keystore: %w", err)
+	}
+
+	key, err := keystore.DecryptKey(keyJSON, password)
+	if err != nil {
+		return nil, fmt.Errorf("decrypting %s: %w", path, err)
+	}
+
+	return &Keystore{path: path, address: key.Address, key: key.PrivateKey}, nil
+}
+
+// FindKeystore returns the keystore file holding wallet in a keystore directory
+func FindKeystore(dir, wallet string) (string, error) {
+	if !common.IsHexAddress(wallet) {
+		return "", fmt.Errorf("invalid wallet address %q", wallet)
+	}
+
+	// The scrypt parameters only matter for new keys; finding one just reads the directory
+	ks := keystore.NewKeyStore(dir, keystore.LightScryptN, keystore.LightScryptP)
+	account, err := ks.Find(accounts.Account{Address: common.HexToAddress(wallet)})
+	if err != nil {
+		return "", fmt.Errorf("no keystore for %s in %s: %w", wallet, dir, err)
+	}
+
+	return account.URL.Path, nil
+}
+
+// Address returns the keystore's account
+func (k *Keystore) Address() common.Address {
+	return k.address
+}
+
+// TransactOpts signs with the decrypted key for the given chain
+func (k *Keystore) TransactOpts(ctx context.Context, chainID *big.Int) (*bind.TransactOpts, error) {
+	opts, err := bind.NewKeyedTransactorWithChainID(k.key, chainID)
+	if err != nil {
+		return nil, err
+	}
+	opts.Context = ctx
+
+	return opts, nil
+}
@@ -0,0 +1,118 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// MockVersion is the account_version reported by the mock signer, the Clef external API it mimics
+const MockVersion = "6.1.0"
+
+// MockRequest describes one transaction the mock signer was asked to sign
+type MockRequest struct {
+	Tx  *types.Transaction // the signed transaction, nil when signing failed
+	Err error              // why the request was refused
+}
+
+// mockAPI is the account namespace served by the mock signer. It approves every
+// request for its single key, so it must only ever listen on a loopback address.
+type mockAPI struct {
+	signer   *Keystore
+	requests chan<- MockRequest
+}
+
+// signTransactionResult is Clef's reply to account_signTransaction
+type signTransactionResult struct {
+	Raw hexutil.Bytes      `json:"raw"`
+	Tx  *types.Transaction `json:"tx"`
+}
+
+// Version implements account_version
+func (m *mockAPI) Version() string {
+	return MockVersion
+}
+
+// List implements account_list
+func (m *mockAPI) List() []common.Address {
+	return []common.Address{m.signer.Address()}
+}
+
+// SignTransaction implements account_signTransaction
+func (m *mockAPI) SignTransaction(ctx context.Context, args apitypes.SendTxArgs) (*signTransactionResult, error) {
+	signed, err := m.sign(args)
+	if m.requests != nil {
+		select {
+		case m.requests <- MockRequest{Tx: signed, Err: err}:
+		case <-ctx.Done():
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := signed.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	return &signTransactionResult{Raw: raw, Tx: signed}, nil
+}
+
+// sign signs the requested transaction, which must be from the mock's account and name its chain
+func (m *mockAPI) sign(args apitypes.SendTxArgs) (*types.Transaction, error) {
+	if args.From.Address() != m.signer.Address() {
+		return nil, fmt.Errorf("unknown account %s", args.From.Address().Hex())
+	}
+	if args.ChainID == nil {
+		return nil, errors.New("chainId is required")
+	}
+
+	tx, err := args.ToTransaction()
+	if err != nil {
+		return nil, err
+	}
+
+	return types.SignTx(tx, types.LatestSignerForChainID(args.ChainID.ToInt()), m.signer.key)
+}
+
+// ServeMock serves a Clef-compatible signer for the keystore's account on listener
+// until it is closed. Every signing request is approved and, when requests is not
+// nil, reported on it. It is meant for testing the external signer path locally.
+func ServeMock(listener net.Listener, signer *Keystore, requests chan<- MockRequest) error {
+	if !isLoopback(listener.Addr()) {
+		return fmt.Errorf("the mock signer approves every request and only listens on loopback addresses, not %s", listener.Addr())
+	}
+
+	server := rpc.NewServer()
+	if err := server.RegisterName("account", &mockAPI{signer: signer, requests: requests}); err != nil {
+		return err
+	}
+	defer server.Stop()
+
+	err := http.Serve(listener, server)
+	if errors.Is(err, net.ErrClosed) {
+		return nil
+	}
+
+	return err
+}
+
+// isLoopback reports whether a listener address is only reachable from this machine
+func isLoopback(addr net.Addr) bool {
+	tcp, ok := addr.(*net.TCPAddr)
+	if !ok {
+		// Unix sockets are local by nature
+		return addr.Network() == "unix"
+	}
+
+	return tcp.IP.IsLoopback()
+}
@@ -0,0 +1,120 @@
+// Package signer produces the transaction options every trading command signs
+// with. A Signer holds one account; the keystore signer unlocks it from a
+// go-ethereum keystore JSON file and the external signer delegates to a
+// Clef-compatible JSON-RPC endpoint.
+package signer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/password"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Signer signs transactions for a single account
+type Signer interface {
+	// Address returns the account transactions are sent from
+	Address() common.Address
+
+	// TransactOpts returns options that sign for the account on the given chain
+	TransactOpts(ctx context.Context, chainID *big.Int) (*bind.TransactOpts, error)
+}
+
+// ChainIDReader is the part of an Ethereum client needed to sign for the right chain
+type ChainIDReader interface {
+	ChainID(ctx context.Context) (*big.Int, error)
+}
+
+// Options selects and unlocks a signer
+type Options struct {
+	KeystoreFile string           // keystore JSON file holding the account
+	KeystoreDir  string           // directory searched for Wallet's file when KeystoreFile is empty
+	SignerURL    string           // external signer endpoint, used instead of a keystore when set
+	Wallet       string           // expected account address, checked when set
+	Password     password.Options // where the keystore password comes from
+}
+
+// Open unlocks the signer described by opts and checks it holds the expected wallet
+func Open(opts Options) (Signer, error) {
+	if opts.SignerURL != "" {
+		return openExternal(opts)
+	}
+
+	if opts.KeystoreFile == "" {
+		if opts.KeystoreDir == "" || opts.Wallet == "" {
+			return nil, errors.New("--keystore-file, or --wallet with its keystore in --keystore-dir, is required to sign transactions")
+		}
+
+		file, err := FindKeystore(opts.KeystoreDir, opts.Wallet)
+		if err != nil {
+			return nil, err
+		}
+		opts.KeystoreFile = file
+	}
+
+	if opts.Password.Prompt == "" {
+		opts.Password.Prompt = fmt.Sprintf("Password for %s", opts.KeystoreFile)
+	}
+	pass, err := password.Read(opts.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	signer, err := NewKeystore(opts.KeystoreFile, pass)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := CheckWallet(signer, opts.Wallet); err != nil {
+		return nil, err
+	}
+
+	return signer, nil
+}
+
+// openExternal connects to the external signer for --wallet, which it requires
+// since the signer may manage many accounts
+func openExternal(opts Options) (Signer, error) {
+	if opts.KeystoreFile != "" {
+		return nil, errors.New("give either --keystore-file or --signer-url, not both")
+	}
+	if opts.Wallet == "" {
+		return nil, errors.New("--wallet is required with --signer-url")
+	}
+	if !common.IsHexAddress(opts.Wallet) {
+		return nil, fmt.Errorf("invalid wallet address %q", opts.Wallet)
+	}
+
+	return NewExternal(opts.SignerURL, common.HexToAddress(opts.Wallet))
+}
+
+// CheckWallet verifies a signer holds the account given by --wallet. An empty wallet is not checked.
+func CheckWallet(signer Signer, wallet string) error {
+	if wallet == "" {
+		return nil
+	}
+
+	if !common.IsHexAddress(wallet) {
+		return fmt.Errorf("invalid wallet address %q", wallet)
+	}
+
+	if common.HexToAddress(wallet) != signer.Address() {
+		return fmt.Errorf("signer holds %s, not --wallet %s", signer.Address().Hex(), wallet)
+	}
+
+	return nil
+}
+
+// Transactor reads the chain ID from the backend and returns options signing for it
+func Transactor(ctx context.Context, signer Signer, backend ChainIDReader) (*bind.TransactOpts, error) {
+	chainID, err := backend.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("reading chain ID: %w", err)
+	}
+
+	return signer.TransactOpts(ctx, chainID)
+}
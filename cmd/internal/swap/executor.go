@@ -0,0 +1,406 @@
+package swap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/contracts/erc20"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/contracts/uniswapv2"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Backend is what the executor needs to simulate a swap, send it and wait for it
+// to be mined. Both *ethclient.Client and the simulated backend's client satisfy it.
+type Backend interface {
+	bind.ContractBackend
+	bind.DeployBackend
+}
+
+// Result is the outcome of a mined swap
+type Result struct {
+	Transaction *types.Transaction
+	Receipt     *types.Receipt
+
+	// Amounts are the per-hop amounts the pools swapped, read from their Swap
+	// logs: the input first, then the output of each hop, as the router returns them
+	Amounts []*big.Int
+}
+
+// AmountIn returns the amount the first pool took in
+func (r *Result) AmountIn() *big.Int {
+	return r.Amounts[0]
+}
+
+// AmountOut returns the amount the last pool sent out
+func (r *Result) AmountOut() *big.Int {
+	return r.Amounts[len(r.Amounts)-1]
+}
+
+// Simulation is the outcome of running a swap through eth_call without sending it
+type Simulation struct {
+	BlockNumber *big.Int   // block the call ran against, nil for the latest
+	Amounts     []*big.Int // per-hop amounts returned by the router, nil if it reverted
+	GasUsed     uint64     // eth_estimateGas for the same call, 0 if it reverted
+
+	// Revert is the router's revert reason, empty when the call succeeded
+	Reverted bool
+	Revert   string
+
+	// Approved is set when the account had not approved the router for the
+	// input yet and the swap was run after that approval
+	Approved bool
+}
+
+// AmountOut returns the final amount the router returned
+func (s *Simulation) AmountOut() *big.Int {
+	return s.Amounts[len(s.Amounts)-1]
+}
+
+// Executor simulates and sends quoted routes through a Uniswap V2 router.
+// Simulation and execution pack exactly the same calldata.
+type Executor struct {
+	backend  Backend
+	router   common.Address
+	abi      *abi.ABI
+	contract *bind.BoundContract
+}
+
+// NewExecutor binds the router at the given address
+func NewExecutor(backend Backend, router common.Address) (*Executor, error) {
+	parsed, err := uniswapv2.IUniswapV2Router02MetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Executor{
+		backend:  backend,
+		router:   router,
+		abi:      parsed,
+		contract: bind.NewBoundContract(router, *parsed, backend, backend, backend),
+	}, nil
+}
+
+// Router returns the address of the router swaps are sent to
+func (e *Executor) Router() common.Address {
+	return e.router
+}
+
+// WETH returns the wrapped ether token the router uses
+func (e *Executor) WETH(ctx context.Context) (common.Address, error) {
+	var out []interface{}
+	if err := e.contract.Call(&bind.CallOpts{Context: ctx}, &out, "WETH"); err != nil {
+		return common.Address{}, err
+	}
+
+	return *abi.ConvertType(out[0], new(common.Address)).(*common.Address), nil
+}
+
+// calldata packs the router swap for the quote with its slippage bound and deadline
+func (e *Executor) calldata(quote *Quote, to common.Address, deadline time.Time) ([]byte, error) {
+	if quote.NativeIn && quote.NativeOut {
+		return nil, errors.New("a swap cannot be native on both sides")
+	}
+	if quote.NativeIn {
+		// The input amount is the ether sent with the call
+		return e.abi.Pack(quote.method(), quote.AmountOutMin, quote.Route.Path(), to, big.NewInt(deadline.Unix()))
+	}
+
+	return e.abi.Pack(quote.method(),
+		quote.AmountIn(), quote.AmountOutMin, quote.Route.Path(), to, big.NewInt(deadline.Unix()))
+}
+
+// Simulate runs the swap from the given account through eth_call and
+// eth_estimateGas at a block (nil for the latest) without sending anything.
+// When the account has not approved the router for an ERC-20 input yet, the
+// swap is run right after that approval in one eth_simulateV1 block instead,
+// so a swap can be checked before any approval is sent.
+// A revert is reported in the Simulation; the error is only set when the node could not be queried.
+func (e *Executor) Simulate(ctx context.Context, from common.Address, quote *Quote, to common.Address, deadline time.Time, blockNumber *big.Int) (*Simulation, error) {
+	data, err := e.calldata(quote, to, deadline)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := ethereum.CallMsg{From: from, To: &e.router, Data: data, Value: quote.value()}
+	approve, err := e.missingApproval(ctx, from, quote, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	if approve != nil {
+		return e.simulateApproved(ctx, *approve, msg, quote, blockNumber)
+	}
+
+	simulation := &Simulation{BlockNumber: blockNumber}
+
+	output, err := e.backend.CallContract(ctx, msg, blockNumber)
+	if err != nil {
+		reason, ok := revertReason(err)
+		if !ok {
+			return nil, fmt.Errorf("calling router: %w", err)
+		}
+		simulation.Reverted, simulation.Revert = true, reason
+		return simulation, nil
+	}
+
+	results, err := e.abi.Unpack(quote.method(), output)
+	if err != nil {
+		return nil, fmt.Errorf("decoding router output: %w", err)
+	}
+	simulation.Amounts = *abi.ConvertType(results[0], new([]*big.Int)).(*[]*big.Int)
+
+	if simulation.GasUsed, err = e.estimateGas(ctx, msg, blockNumber); err != nil {
+		return nil, fmt.Errorf("estimating gas: %w", err)
+	}
+
+	return simulation, nil
+}
+
+// estimateGas runs eth_estimateGas at a block. Pinned blocks need the raw RPC
+// client, since the bind interfaces only estimate against the pending state.
+func (e *Executor) estimateGas(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) (uint64, error) {
+	raw, ok := e.backend.(interface{ Client() *rpc.Client })
+	if blockNumber == nil || !ok {
+		return e.backend.EstimateGas(ctx, msg)
+	}
+
+	var gas hexutil.Uint64
+	if err := raw.Client().CallContext(ctx, &gas, "eth_estimateGas", callArgs(msg), hexutil.EncodeBig(blockNumber)); err != nil {
+		return 0, err
+	}
+
+	return uint64(gas), nil
+}
+
+// missingApproval returns the approve call the router needs to pull the
+// quote's input from the account, nil when the input is ether or the
+// allowance at the block already covers it
+func (e *Executor) missingApproval(ctx context.Context, from common.Address, quote *Quote, blockNumber *big.Int) (*ethereum.CallMsg, error) {
+	if quote.NativeIn {
+		return nil, nil
+	}
+
+	tokenIn := quote.Route.Hops[0].From
+	token, err := erc20.NewIERC20Caller(tokenIn.Address, e.backend)
+	if err != nil {
+		return nil, err
+	}
+	allowance, err := token.Allowance(&bind.CallOpts{Context: ctx, BlockNumber: blockNumber}, from, e.router)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s allowance: %w", tokenIn.Symbol, err)
+	}
+	if allowance.Cmp(quote.AmountIn()) >= 0 {
+		return nil, nil
+	}
+
+	parsed, err := erc20.IERC20MetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	data, err := parsed.Pack("approve", e.router, quote.AmountIn())
+	if err != nil {
+		return nil, err
+	}
+
+	return &ethereum.CallMsg{From: from, To: &tokenIn.Address, Data: data}, nil
+}
+
+// simulateApproved runs the approve call and then the swap in one block
+// through eth_simulateV1. Gas is what the swap used in that block rather than
+// an eth_estimateGas estimate.
+func (e *Executor) simulateApproved(ctx context.Context, approve, msg ethereum.CallMsg, quote *Quote, blockNumber *big.Int) (*Simulation, error) {
+	raw, ok := e.backend.(interface{ Client() *rpc.Client })
+	if !ok {
+		return nil, errors.New("the router is not approved yet and the backend cannot simulate the approval")
+	}
+
+	block := "latest"
+	if blockNumber != nil {
+		block = hexutil.EncodeBig(blockNumber)
+	}
+	opts := map[string]interface{}{
+		"blockStateCalls": []map[string]interface{}{{"calls": []map[string]interface{}{callArgs(approve), callArgs(msg)}}},
+	}
+
+	var blocks []struct {
+		Calls []struct {
+			ReturnData hexutil.Bytes  `json:"returnData"`
+			GasUsed    hexutil.Uint64 `json:"gasUsed"`
+			Status     hexutil.Uint64 `json:"status"`
+			Error      *struct {
+				Message string `json:"message"`
+				Data    string `json:"data"`
+			} `json:"error"`
+		} `json:"calls"`
+	}
+	if err := raw.Client().CallContext(ctx, &blocks, "eth_simulateV1", opts, block); err != nil {
+		return nil, fmt.Errorf("simulating the swap after its approval (the node needs eth_simulateV1): %w", err)
+	}
+	if len(blocks) != 1 || len(blocks[0].Calls) != 2 {
+		return nil, errors.New("eth_simulateV1 did not return the approval and the swap")
+	}
+
+	approved, swapped := blocks[0].Calls[0], blocks[0].Calls[1]
+	if uint64(approved.Status) != types.ReceiptStatusSuccessful {
+		return nil, errors.New("approving the router reverted in the simulation")
+	}
+
+	simulation := &Simulation{BlockNumber: blockNumber, Approved: true}
+	if uint64(swapped.Status) != types.ReceiptStatusSuccessful {
+		simulation.Reverted = true
+		if swapped.Error != nil {
+			if data, ok := revertData(swapped.Error.Data); ok && len(data) > 0 {
+				simulation.Revert = decodeRevert(data)
+			}
+		}
+		return simulation, nil
+	}
+
+	results, err := e.abi.Unpack(quote.method(), swapped.ReturnData)
+	if err != nil {
+		return nil, fmt.Errorf("decoding router output: %w", err)
+	}
+	simulation.Amounts = *abi.ConvertType(results[0], new([]*big.Int)).(*[]*big.Int)
+	simulation.GasUsed = uint64(swapped.GasUsed)
+
+	return simulation, nil
+}
+
+// callArgs converts a call into the transaction arguments of the JSON-RPC API
+func callArgs(msg ethereum.CallMsg) map[string]interface{} {
+	args := map[string]interface{}{
+		"from": msg.From,
+		"to":   msg.To,
+		"data": hexutil.Bytes(msg.Data),
+	}
+	if msg.Value != nil {
+		args["value"] = (*hexutil.Big)(msg.Value)
+	}
+
+	return args
+}
+
+// Swap sends the same router call Simulate runs, waits for the receipt and
+// reads the amounts swapped from the pools' Swap logs. Balances are not used,
+// since on a cycle the output token is also the input.
+func (e *Executor) Swap(ctx context.Context, opts *bind.TransactOpts, quote *Quote, to common.Address, deadline time.Time) (*Result, error) {
+	data, err := e.calldata(quote, to, deadline)
+	if err != nil {
+		return nil, err
+	}
+
+	txOpts := *opts
+	txOpts.Context = ctx
+	txOpts.Value = quote.value()
+
+	result := &Result{}
+	result.Transaction, err = e.contract.RawTransact(&txOpts, data)
+	if err != nil {
+		return nil, fmt.Errorf("sending swap: %w", err)
+	}
+
+	result.Receipt, err = bind.WaitMined(ctx, e.backend, result.Transaction)
+	if err != nil {
+		return nil, fmt.Errorf("waiting for %s: %w", result.Transaction.Hash().Hex(), err)
+	}
+	if result.Receipt.Status != types.ReceiptStatusSuccessful {
+		return result, errors.New("swap transaction reverted")
+	}
+
+	if result.Amounts, err = swappedAmounts(quote.Route, result.Receipt.Logs); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// swappedAmounts reads the per-hop amounts of a route from the Swap logs its
+// pools emitted, taking one log per hop in route order
+func swappedAmounts(route *Route, logs []*types.Log) ([]*big.Int, error) {
+	pair, err := uniswapv2.NewIUniswapV2PairFilterer(common.Address{}, nil)
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := uniswapv2.IUniswapV2PairMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	swapID := parsed.Events["Swap"].ID
+
+	amounts := make([]*big.Int, 0, len(route.Hops)+1)
+	next := 0
+	for _, hop := range route.Hops {
+		var event *uniswapv2.IUniswapV2PairSwap
+		for ; next < len(logs) && event == nil; next++ {
+			log := logs[next]
+			if log.Address != hop.Pool || len(log.Topics) == 0 || log.Topics[0] != swapID {
+				continue
+			}
+			if event, err = pair.ParseSwap(*log); err != nil {
+				return nil, fmt.Errorf("decoding Swap log of %s: %w", hop.PoolName, err)
+			}
+		}
+		if event == nil {
+			return nil, fmt.Errorf("no Swap log from %s in the receipt", hop.PoolName)
+		}
+
+		amountIn, amountOut := event.Amount1In, event.Amount0Out
+		if hop.ZeroForOne {
+			amountIn, amountOut = event.Amount0In, event.Amount1Out
+		}
+		if len(amounts) == 0 {
+			amounts = append(amounts, amountIn)
+		}
+		amounts = append(amounts, amountOut)
+	}
+
+	return amounts, nil
+}
+
+// revertReason extracts the Error(string) reason from an eth_call revert.
+// It reports false when err is not a revert at all.
+func revertReason(err error) (string, bool) {
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		if data, ok := revertData(dataErr.ErrorData()); ok {
+			return decodeRevert(data), true
+		}
+	}
+
+	if strings.Contains(err.Error(), "execution reverted") {
+		return "", true
+	}
+
+	return "", false
+}
+
+// decodeRevert returns the Error(string) reason of revert data, or the data in hex
+func decodeRevert(data []byte) string {
+	if reason, err := abi.UnpackRevert(data); err == nil {
+		return reason
+	}
+
+	return hexutil.Encode(data)
+}
+
+// revertData decodes the data attached to a revert error, which nodes return as a hex string
+func revertData(data interface{}) ([]byte, bool) {
+	switch data := data.(type) {
+	case string:
+		decoded, err := hexutil.Decode(data)
+		return decoded, err == nil
+	case []byte:
+		return data, true
+	}
+
+	return nil, false
+}
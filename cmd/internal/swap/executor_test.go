@@ -0,0 +1,241 @@
+package swap
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/contracts/erc20"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/poolreader"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/registry"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/testchain"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// tokens returns amount whole tokens of 18 decimals in base units
+func tokens(amount int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(amount), big.NewInt(1_000_000_000_000_000_000))
+}
+
+// cycleChain is a simulated chain with three pools forming a profitable
+// eUSD → eEUR → eGBP → eUSD cycle and a router trading through them
+type cycleChain struct {
+	*testchain.Chain
+	router        common.Address
+	pools         *registry.Registry
+	usd, eur, gbp common.Address
+}
+
+func newCycleChain(t *testing.T) *cycleChain {
+	genesis := testchain.NewGenesis(t)
+	c := &cycleChain{
+		pools: registry.New(),
+		usd:   genesis.Token("eUSD", 18),
+		eur:   genesis.Token("eEUR", 18),
+		gbp:   genesis.Token("eGBP", 18),
+	}
+	genesis.Mint(c.usd, genesis.Account(), tokens(1000))
+
+	// eGBP is cheap against eUSD, so going round the cycle ends with more eUSD
+	pairs := []struct {
+		name           string
+		tokenA, tokenB common.Address
+		reserveA       *big.Int
+		reserveB       *big.Int
+	}{
+		{"eUSD_eEUR", c.usd, c.eur, tokens(10_000), tokens(10_000)},
+		{"eEUR_eGBP", c.eur, c.gbp, tokens(10_000), tokens(10_000)},
+		{"eGBP_eUSD", c.gbp, c.usd, tokens(10_000), tokens(12_000)},
+	}
+	var addresses []common.Address
+	for _, pair := range pairs {
+		address := genesis.Pair(pair.tokenA, pair.tokenB, pair.reserveA, pair.reserveB)
+		addresses = append(addresses, address)
+		if err := c.pools.Add(&registry.Pool{Name: pair.name, Address: address, FeeBps: 30}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c.router = genesis.Router(c.usd, addresses...)
+	c.Chain = genesis.Start(t)
+
+	return c
+}
+
+// quoteCycle quotes the eUSD cycle at its optimal input
+func (c *cycleChain) quoteCycle(t *testing.T, slippageBps uint64) *Quote {
+	t.Helper()
+
+	ctx := context.Background()
+	reader := poolreader.NewLive(c.Client())
+	route, err := ResolveRoute(ctx, reader, c.pools, []common.Address{c.usd, c.eur, c.gbp, c.usd})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !route.IsCycle() || len(route.Hops) != 3 {
+		t.Fatalf("route %v is not a three hop cycle", route.Path())
+	}
+
+	quote, err := QuoteRoute(ctx, reader, route, nil, slippageBps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if quote.AmountOut().Cmp(quote.AmountIn()) <= 0 {
+		t.Fatalf("quoted cycle is not profitable: %s → %s", quote.AmountIn(), quote.AmountOut())
+	}
+
+	return quote
+}
+
+// balance returns the funded account's eUSD balance
+func (c *cycleChain) balance(t *testing.T) *big.Int {
+	t.Helper()
+
+	token, err := erc20.NewIERC20Caller(c.usd, c.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	balance, err := token.BalanceOf(&bind.CallOpts{}, c.Account())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return balance
+}
+
+// checkAmounts compares per-hop amounts with the quoted ones
+func checkAmounts(t *testing.T, what string, got, want []*big.Int) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("%s amounts = %v, want %v", what, got, want)
+	}
+	for i := range want {
+		if got[i].Cmp(want[i]) != 0 {
+			t.Errorf("%s amounts[%d] = %s, want %s", what, i, got[i], want[i])
+		}
+	}
+}
+
+func TestSwapCycle(t *testing.T) {
+	chain := newCycleChain(t)
+	quote := chain.quoteCycle(t, 50)
+
+	ctx := context.Background()
+	executor, err := NewExecutor(chain.Client(), chain.router)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if weth, err := executor.WETH(ctx); err != nil || weth != chain.usd {
+		t.Errorf("WETH() = %s, %v, want %s", weth.Hex(), err, chain.usd.Hex())
+	}
+	chain.Approve(t, chain.usd, chain.router, quote.AmountIn())
+
+	deadline := time.Now().Add(time.Hour)
+	simulation, err := executor.Simulate(ctx, chain.Account(), quote, chain.Account(), deadline, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if simulation.Reverted {
+		t.Fatalf("simulation reverted: %q", simulation.Revert)
+	}
+	checkAmounts(t, "simulated", simulation.Amounts, quote.Amounts)
+	if simulation.GasUsed == 0 {
+		t.Error("simulation estimated no gas")
+	}
+
+	before := chain.balance(t)
+	result, err := executor.Swap(ctx, chain.TransactOpts(t), quote, chain.Account(), deadline)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkAmounts(t, "swapped", result.Amounts, quote.Amounts)
+
+	// The cycle's input and output are the same token, so the balance changes by the profit alone
+	profit := new(big.Int).Sub(result.AmountOut(), result.AmountIn())
+	if profit.Sign() <= 0 {
+		t.Errorf("profit = %s, want a positive profit", profit)
+	}
+	if change := new(big.Int).Sub(chain.balance(t), before); change.Cmp(profit) != 0 {
+		t.Errorf("balance changed by %s, reported profit %s", change, profit)
+	}
+}
+
+func TestSwapBelowMinimumFails(t *testing.T) {
+	chain := newCycleChain(t)
+	quote := chain.quoteCycle(t, 0)
+	quote.AmountOutMin = new(big.Int).Add(quote.AmountOut(), big.NewInt(1))
+
+	executor, err := NewExecutor(chain.Client(), chain.router)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain.Approve(t, chain.usd, chain.router, quote.AmountIn())
+
+	before := chain.balance(t)
+	if _, err := executor.Swap(context.Background(), chain.TransactOpts(t), quote, chain.Account(), time.Now().Add(time.Hour)); err == nil {
+		t.Error("a swap short of its minimum output succeeded")
+	}
+	if after := chain.balance(t); after.Cmp(before) != 0 {
+		t.Errorf("balance changed from %s to %s", before, after)
+	}
+}
+
+func TestSimulateReportsRevert(t *testing.T) {
+	chain := newCycleChain(t)
+	quote := chain.quoteCycle(t, 0)
+	quote.AmountOutMin = new(big.Int).Add(quote.AmountOut(), big.NewInt(1))
+
+	executor, err := NewExecutor(chain.Client(), chain.router)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain.Approve(t, chain.usd, chain.router, quote.AmountIn())
+
+	simulation, err := executor.Simulate(context.Background(), chain.Account(), quote, chain.Account(), time.Now().Add(time.Hour), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !simulation.Reverted || simulation.Revert != "UniswapV2Router: INSUFFICIENT_OUTPUT_AMOUNT" {
+		t.Errorf("simulation = reverted %t %q, want the router's insufficient output revert", simulation.Reverted, simulation.Revert)
+	}
+}
+
+func TestSimulateBeforeApproval(t *testing.T) {
+	chain := newCycleChain(t)
+	quote := chain.quoteCycle(t, 50)
+	ctx := context.Background()
+	deadline := time.Now().Add(time.Hour)
+
+	executor, err := NewExecutor(chain.Client(), chain.router)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Without an allowance the swap runs after a simulated approval, which is not sent
+	block := chain.BlockNumber(t)
+	simulation, err := executor.Simulate(ctx, chain.Account(), quote, chain.Account(), deadline, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !simulation.Approved || simulation.Reverted {
+		t.Fatalf("simulation = approved %t, reverted %t %q", simulation.Approved, simulation.Reverted, simulation.Revert)
+	}
+	checkAmounts(t, "simulated", simulation.Amounts, quote.Amounts)
+	if simulation.GasUsed == 0 {
+		t.Error("simulation used no gas")
+	}
+	if chain.BlockNumber(t) != block {
+		t.Error("the simulation sent a transaction")
+	}
+
+	quote.AmountOutMin = new(big.Int).Add(quote.AmountOut(), big.NewInt(1))
+	simulation, err = executor.Simulate(ctx, chain.Account(), quote, chain.Account(), deadline, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !simulation.Reverted || simulation.Revert != "UniswapV2Router: INSUFFICIENT_OUTPUT_AMOUNT" {
+		t.Errorf("simulation = reverted %t %q, want the router's insufficient output revert", simulation.Reverted, simulation.Revert)
+	}
+}
@@ -0,0 +1,88 @@
+package swap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/poolreader"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/registry"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// FindRoutes returns every route from one token to another through a single
+// registered pool or through one intermediate token, direct routes first.
+// Pools are taken in name order so the same tokens always give the same routes.
+func FindRoutes(ctx context.Context, reader poolreader.PoolReader, pools *registry.Registry, from, to common.Address) ([]*Route, error) {
+	if from == to {
+		return nil, errors.New("input and output tokens are the same")
+	}
+
+	// Every pool can be traded in both directions
+	var hops []Hop
+	for _, pool := range pools.Pools() {
+		token0, token1, err := reader.Tokens(ctx, pool.Address)
+		if err != nil {
+			return nil, fmt.Errorf("reading tokens of %s: %w", pool.Name, err)
+		}
+
+		hop := Hop{PoolName: pool.Name, Pool: pool.Address, FeeBps: pool.FeeBps}
+		forward, backward := hop, hop
+		forward.From, forward.To, forward.ZeroForOne = token0, token1, true
+		backward.From, backward.To = token1, token0
+		hops = append(hops, forward, backward)
+	}
+
+	var direct, twoHop []*Route
+	for _, first := range hops {
+		if first.From.Address != from {
+			continue
+		}
+		if first.To.Address == to {
+			direct = append(direct, &Route{Hops: []Hop{first}})
+			continue
+		}
+
+		for _, second := range hops {
+			if second.From.Address == first.To.Address && second.To.Address == to && second.Pool != first.Pool {
+				twoHop = append(twoHop, &Route{Hops: []Hop{first, second}})
+			}
+		}
+	}
+
+	routes := append(direct, twoHop...)
+	if len(routes) == 0 {
+		return nil, fmt.Errorf("no registered pool connects %s and %s directly or through one token", from.Hex(), to.Hex())
+	}
+
+	return routes, nil
+}
+
+// BestQuote quotes amountIn along every route and returns the quote with the
+// largest output. Routes that cannot be quoted, such as pools without
+// liquidity, are skipped; on a tie the earlier route wins.
+func BestQuote(ctx context.Context, reader poolreader.PoolReader, routes []*Route, amountIn *big.Int, slippageBps uint64) (*Quote, error) {
+	if amountIn == nil {
+		return nil, errors.New("an input amount is required")
+	}
+
+	var best *Quote
+	var lastErr error
+	for _, route := range routes {
+		quote, err := QuoteRoute(ctx, reader, route, amountIn, slippageBps)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if best == nil || quote.AmountOut().Cmp(best.AmountOut()) > 0 {
+			best = quote
+		}
+	}
+
+	if best == nil {
+		return nil, fmt.Errorf("no route could be quoted: %w", lastErr)
+	}
+
+	return best, nil
+}
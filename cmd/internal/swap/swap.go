@@ -0,0 +1,205 @@
+// Package swap turns a token path into a Uniswap V2 router swap: it resolves
+// the path to registered pools, quotes every hop from current reserves,
+// applies the slippage bound and sends the router swap, swapping native ETH
+// in or out through WETH when asked.
+package swap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/poolreader"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/registry"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/swapmath"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Hop is one pool along a route, oriented in the direction of the trade
+type Hop struct {
+	PoolName string
+	Pool     common.Address
+	From     poolreader.Token
+	To       poolreader.Token
+	FeeBps   uint64
+
+	// ZeroForOne is true when the pool's token0 is sold for its token1
+	ZeroForOne bool
+}
+
+// Route is a token path resolved to the pools connecting each pair of tokens
+type Route struct {
+	Hops []Hop
+}
+
+// Tokens returns every token along the route, including the first
+func (r *Route) Tokens() []poolreader.Token {
+	tokens := []poolreader.Token{r.Hops[0].From}
+	for _, hop := range r.Hops {
+		tokens = append(tokens, hop.To)
+	}
+
+	return tokens
+}
+
+// Path returns the token addresses in the form the router expects
+func (r *Route) Path() []common.Address {
+	tokens := r.Tokens()
+	path := make([]common.Address, len(tokens))
+	for i, token := range tokens {
+		path[i] = token.Address
+	}
+
+	return path
+}
+
+// IsCycle reports whether the route ends with the token it starts with
+func (r *Route) IsCycle() bool {
+	return r.Hops[0].From.Address == r.Hops[len(r.Hops)-1].To.Address
+}
+
+// ResolveRoute maps a path of token addresses to the registered pools that
+// trade each consecutive pair
+func ResolveRoute(ctx context.Context, reader poolreader.PoolReader, pools *registry.Registry, path []common.Address) (*Route, error) {
+	if len(path) < 2 {
+		return nil, errors.New("a path needs at least two tokens")
+	}
+
+	route := &Route{}
+	for i := 0; i+1 < len(path); i++ {
+		hop, err := findHop(ctx, reader, pools, path[i], path[i+1])
+		if err != nil {
+			return nil, fmt.Errorf("hop %s → %s: %w", path[i].Hex(), path[i+1].Hex(), err)
+		}
+		route.Hops = append(route.Hops, *hop)
+	}
+
+	return route, nil
+}
+
+// findHop finds the pool trading from against to, in either token order.
+// Pools are tried in name order so the same path always resolves the same way.
+func findHop(ctx context.Context, reader poolreader.PoolReader, pools *registry.Registry, from, to common.Address) (*Hop, error) {
+	for _, pool := range pools.Pools() {
+		token0, token1, err := reader.Tokens(ctx, pool.Address)
+		if err != nil {
+			return nil, fmt.Errorf("reading tokens of %s: %w", pool.Name, err)
+		}
+
+		hop := &Hop{PoolName: pool.Name, Pool: pool.Address, FeeBps: pool.FeeBps}
+		switch {
+		case token0.Address == from && token1.Address == to:
+			hop.From, hop.To, hop.ZeroForOne = token0, token1, true
+		case token1.Address == from && token0.Address == to:
+			hop.From, hop.To = token1, token0
+		default:
+			continue
+		}
+
+		return hop, nil
+	}
+
+	return nil, errors.New("no pool found")
+}
+
+// Quote is the expected outcome of a route at the current reserves
+type Quote struct {
+	Route        *Route
+	Amounts      []*big.Int // amount held after each hop, starting with the input
+	AmountOutMin *big.Int   // final amount after the slippage bound
+	SlippageBps  uint64
+
+	// NativeIn and NativeOut swap native ETH for the route's leading or
+	// trailing WETH, through swapExactETHForTokens or swapExactTokensForETH
+	NativeIn  bool
+	NativeOut bool
+}
+
+// method returns the router function that swaps the quote
+func (q *Quote) method() string {
+	switch {
+	case q.NativeIn:
+		return "swapExactETHForTokens"
+	case q.NativeOut:
+		return "swapExactTokensForETH"
+	default:
+		return "swapExactTokensForTokens"
+	}
+}
+
+// value returns the ether sent with the swap
+func (q *Quote) value() *big.Int {
+	if q.NativeIn {
+		return q.AmountIn()
+	}
+
+	return nil
+}
+
+// AmountIn returns the quoted input amount
+func (q *Quote) AmountIn() *big.Int {
+	return q.Amounts[0]
+}
+
+// AmountOut returns the expected final amount
+func (q *Quote) AmountOut() *big.Int {
+	return q.Amounts[len(q.Amounts)-1]
+}
+
+// QuoteRoute simulates amountIn through every hop of the route from current reserves.
+// A nil amountIn on a cycle uses the input that maximises the cycle's profit.
+func QuoteRoute(ctx context.Context, reader poolreader.PoolReader, route *Route, amountIn *big.Int, slippageBps uint64) (*Quote, error) {
+	hops := make([]swapmath.Hop, len(route.Hops))
+	for i, hop := range route.Hops {
+		reserves, err := reader.Reserves(ctx, hop.Pool)
+		if err != nil {
+			return nil, fmt.Errorf("reading reserves of %s: %w", hop.PoolName, err)
+		}
+
+		hops[i] = swapmath.Hop{ReserveIn: reserves.Reserve0, ReserveOut: reserves.Reserve1, FeeBps: hop.FeeBps}
+		if !hop.ZeroForOne {
+			hops[i].ReserveIn, hops[i].ReserveOut = reserves.Reserve1, reserves.Reserve0
+		}
+	}
+
+	if amountIn == nil {
+		if !route.IsCycle() {
+			return nil, errors.New("an input amount is required for a path that is not a cycle")
+		}
+		amountIn = swapmath.OptimalInput(hops)
+		if amountIn == nil {
+			return nil, errors.New("the cycle is not profitable at current reserves")
+		}
+	}
+
+	amounts, err := swapmath.GetAmountsOut(amountIn, hops)
+	if err != nil {
+		return nil, err
+	}
+
+	quote := &Quote{
+		Route:        route,
+		Amounts:      amounts,
+		AmountOutMin: MinOut(amounts[len(amounts)-1], slippageBps),
+		SlippageBps:  slippageBps,
+	}
+
+	return quote, nil
+}
+
+// SlippageBps converts a slippage percentage such as 0.5 into basis points
+func SlippageBps(percent float64) (uint64, error) {
+	if percent < 0 || percent >= 100 || math.IsNaN(percent) {
+		return 0, fmt.Errorf("slippage must be between 0 and 100%%, got %.2f%%", percent)
+	}
+
+	return uint64(math.Round(percent * 100)), nil
+}
+
+// MinOut applies a slippage bound to an expected output, rounding down
+func MinOut(amount *big.Int, slippageBps uint64) *big.Int {
+	out := new(big.Int).Mul(amount, big.NewInt(int64(swapmath.BpsDenominator-slippageBps)))
+	return out.Quo(out, big.NewInt(swapmath.BpsDenominator))
+}
@@ -0,0 +1,141 @@
+package swap
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/poolreader"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/registry"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestResolveRoute(t *testing.T) {
+	ctx := context.Background()
+	pools := registry.Builtin()
+	reader := poolreader.NewFake(pools)
+
+	usd, eur := tokenAddresses(t, reader, pools, "eUSD_eEUR_Pool")
+	_, gbp := tokenAddresses(t, reader, pools, "eEUR_eGBP_Pool")
+	route, err := ResolveRoute(ctx, reader, pools, []common.Address{usd, eur, gbp})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(route.Hops) != 2 || route.IsCycle() {
+		t.Fatalf("route has %d hops, cycle %t", len(route.Hops), route.IsCycle())
+	}
+	for i, want := range []string{"eUSD", "eEUR", "eGBP"} {
+		if got := route.Tokens()[i].Symbol; got != want {
+			t.Errorf("token %d = %s, want %s", i, got, want)
+		}
+	}
+
+	// Each hop sells the pool's token0 only when it goes that way round
+	for _, hop := range route.Hops {
+		token0, _, err := reader.Tokens(ctx, hop.Pool)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hop.ZeroForOne != (hop.From == token0) {
+			t.Errorf("%s: ZeroForOne = %t selling %s", hop.PoolName, hop.ZeroForOne, hop.From.Symbol)
+		}
+	}
+
+	// The same pool traded the other way round
+	back, err := ResolveRoute(ctx, reader, pools, []common.Address{eur, usd})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if back.Hops[0].Pool != route.Hops[0].Pool || back.Hops[0].ZeroForOne == route.Hops[0].ZeroForOne {
+		t.Errorf("reverse hop %+v does not mirror %+v", back.Hops[0], route.Hops[0])
+	}
+
+	for _, path := range [][]common.Address{{usd}, {usd, common.HexToAddress("0x0bad")}} {
+		if _, err := ResolveRoute(ctx, reader, pools, path); err == nil {
+			t.Errorf("path %v resolved", path)
+		}
+	}
+}
+
+func TestSlippage(t *testing.T) {
+	tests := []struct {
+		percent float64
+		bps     uint64
+		ok      bool
+	}{
+		{0.5, 50, true},
+		{0, 0, true},
+		{1.234, 123, true},
+		{-1, 0, false},
+		{100, 0, false},
+	}
+	for _, test := range tests {
+		bps, err := SlippageBps(test.percent)
+		if (err == nil) != test.ok || bps != test.bps {
+			t.Errorf("SlippageBps(%v) = %d, %v", test.percent, bps, err)
+		}
+	}
+
+	if got := MinOut(big.NewInt(1999), 50); got.Int64() != 1989 {
+		t.Errorf("MinOut(1999, 50) = %s, want 1989", got)
+	}
+}
+
+func TestFindRoutes(t *testing.T) {
+	ctx := context.Background()
+	pools := registry.Builtin()
+	reader := poolreader.NewFake(pools)
+
+	usd, gbp := tokenAddresses(t, reader, pools, "eUSD_eGBP_Pool")
+	routes, err := FindRoutes(ctx, reader, pools, usd, gbp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(routes) < 2 || len(routes[0].Hops) != 1 || routes[0].Hops[0].PoolName != "eUSD_eGBP_Pool" {
+		t.Fatalf("routes do not start with the direct pool: %d routes, first %+v", len(routes), routes[0].Hops)
+	}
+	for _, route := range routes[1:] {
+		if len(route.Hops) != 2 {
+			t.Fatalf("a later route has %d hops", len(route.Hops))
+		}
+		first, second := route.Hops[0], route.Hops[1]
+		if first.From.Address != usd || first.To != second.From || second.To.Address != gbp {
+			t.Errorf("route through %s and %s does not go from eUSD to eGBP through one token", first.PoolName, second.PoolName)
+		}
+	}
+
+	// The best quote is at least as good as every route it was chosen from
+	best, err := BestQuote(ctx, reader, routes, big.NewInt(1e18), 50)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, route := range routes {
+		quote, err := QuoteRoute(ctx, reader, route, big.NewInt(1e18), 50)
+		if err == nil && quote.AmountOut().Cmp(best.AmountOut()) > 0 {
+			t.Errorf("route through %s quotes %s, more than the best %s", route.Hops[0].PoolName, quote.AmountOut(), best.AmountOut())
+		}
+	}
+
+	if _, err := FindRoutes(ctx, reader, pools, usd, usd); err == nil {
+		t.Error("a route from a token to itself was found")
+	}
+	if _, err := BestQuote(ctx, reader, routes, nil, 50); err == nil {
+		t.Error("a quote without an input amount was made")
+	}
+}
+
+// tokenAddress returns the addresses of a pool's tokens in the order of its name
+func tokenAddresses(t *testing.T, reader poolreader.PoolReader, pools *registry.Registry, name string) (common.Address, common.Address) {
+	t.Helper()
+
+	pool, _ := pools.Pool(name)
+	token0, token1, err := reader.Tokens(context.Background(), pool.Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token0.Symbol != pool.Token0.Symbol {
+		token0, token1 = token1, token0
+	}
+
+	return token0.Address, token1.Address
+}
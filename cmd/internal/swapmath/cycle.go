@@ -0,0 +1,68 @@
+package swapmath
+
+import "math/big"
+
+// cyclePrec is the big.Float precision used when composing many hops; the
+// intermediate products grow with every reserve multiplied in
+const cyclePrec = 512
+
+// OptimalInput returns the input that maximises amountOut - amountIn for a path
+// that starts and ends with the same token, or nil when no input is profitable.
+/*
+	A single hop maps an input x to g*b*x / (a + g*x). Functions of the form
+	p*x / (q + r*x) are closed under composition:
+
+		(p2,q2,r2) after (p1,q1,r1) = (p2*p1, q2*q1, q2*r1 + r2*p1)
+
+	so the whole path behaves like one virtual pool f(x) = p*x / (q + r*x).
+	Profit f(x) - x is maximal where f'(x) = p*q / (q + r*x)^2 = 1, that is at
+	x = (sqrt(p*q) - q) / r, and only exists when p > q.
+*/
+func OptimalInput(hops []Hop) *big.Int {
+	if len(hops) == 0 {
+		return nil
+	}
+
+	p := newCycleFloat().SetInt64(1)
+	q := newCycleFloat().SetInt64(1)
+	r := newCycleFloat()
+
+	for _, hop := range hops {
+		if hop.ReserveIn.Sign() <= 0 || hop.ReserveOut.Sign() <= 0 || hop.FeeBps >= BpsDenominator {
+			return nil
+		}
+
+		g := newCycleFloat().Quo(newCycleFloat().SetUint64(BpsDenominator-hop.FeeBps), newCycleFloat().SetUint64(BpsDenominator))
+		hopP := newCycleFloat().Mul(g, newCycleFloat().SetInt(hop.ReserveOut))
+		hopQ := newCycleFloat().SetInt(hop.ReserveIn)
+
+		// r = hopQ*r + g*p, computed before p and q are updated
+		nextR := newCycleFloat().Mul(hopQ, r)
+		nextR.Add(nextR, newCycleFloat().Mul(g, p))
+
+		p.Mul(hopP, p)
+		q.Mul(hopQ, q)
+		r = nextR
+	}
+
+	if p.Cmp(q) <= 0 {
+		return nil
+	}
+
+	x := newCycleFloat().Mul(p, q)
+	x.Sqrt(x)
+	x.Sub(x, q)
+	x.Quo(x, r)
+
+	amountIn, _ := x.Int(nil)
+	if amountIn.Sign() <= 0 {
+		return nil
+	}
+
+	return amountIn
+}
+
+// newCycleFloat returns a zero big.Float at the cycle precision
+func newCycleFloat() *big.Float {
+	return new(big.Float).SetPrec(cyclePrec)
+}
@@ -0,0 +1,65 @@
+package swapmath
+
+import (
+	"math/big"
+	"testing"
+)
+
+// cycleHops is eUSD → eEUR → eGBP → eUSD through pools of the given whole-token reserves
+func cycleHops(t *testing.T, lastOut string) []Hop {
+	pool := func(in, out string) Hop {
+		return Hop{ReserveIn: number(t, in+"000000000000000000"), ReserveOut: number(t, out+"000000000000000000"), FeeBps: 30}
+	}
+	return []Hop{pool("10000", "10000"), pool("10000", "10000"), pool("10000", lastOut)}
+}
+
+func TestOptimalInput(t *testing.T) {
+	hops := cycleHops(t, "12000")
+	amountIn := OptimalInput(hops)
+	if amountIn == nil {
+		t.Fatal("no input for a profitable cycle")
+	}
+
+	profit := func(in *big.Int) *big.Int {
+		amounts, err := GetAmountsOut(in, hops)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return new(big.Int).Sub(amounts[len(amounts)-1], in)
+	}
+	best := profit(amountIn)
+	if best.Sign() <= 0 {
+		t.Fatalf("profit at %s = %s", amountIn, best)
+	}
+
+	// Nearby inputs earn no more, within the integer rounding of the swaps
+	step := number(t, "1000000000000000")
+	for _, in := range []*big.Int{
+		new(big.Int).Sub(amountIn, step),
+		new(big.Int).Add(amountIn, step),
+		new(big.Int).Quo(amountIn, big.NewInt(2)),
+		new(big.Int).Mul(amountIn, big.NewInt(2)),
+	} {
+		if profit(in).Cmp(best) > 0 {
+			t.Errorf("input %s earns %s, more than %s at %s", in, profit(in), best, amountIn)
+		}
+	}
+}
+
+func TestOptimalInputUnprofitable(t *testing.T) {
+	tests := []struct {
+		name string
+		hops []Hop
+	}{
+		{"balanced", cycleHops(t, "10000")},
+		{"skew inside the fees", cycleHops(t, "10050")},
+		{"losing direction", cycleHops(t, "8000")},
+		{"no hops", nil},
+		{"empty pool", []Hop{{ReserveIn: big.NewInt(0), ReserveOut: big.NewInt(1), FeeBps: 30}}},
+	}
+	for _, test := range tests {
+		if got := OptimalInput(test.hops); got != nil {
+			t.Errorf("%s: OptimalInput = %s, want nil", test.name, got)
+		}
+	}
+}
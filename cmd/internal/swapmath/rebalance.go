@@ -0,0 +1,117 @@
+package swapmath
+
+import (
+	"errors"
+	"math/big"
+)
+
+// floatPrec is the big.Float precision used by the closed-form solvers
+const floatPrec = 256
+
+// ErrBalanced is returned when a pool is already at its target ratio
+var ErrBalanced = errors.New("pool is already at its target ratio")
+
+// Rebalance is the trade that moves a pool's reserve ratio to a target
+type Rebalance struct {
+	ZeroForOne bool     // true when token0 is sold for token1
+	AmountIn   *big.Int // exact input that brings reserve0/reserve1 to the target
+	AmountOut  *big.Int // output of AmountIn through the pool
+
+	// Profit is AmountOut valued at the target ratio minus AmountIn,
+	// in units of the input token. It is negative when fees outweigh the imbalance.
+	Profit        *big.Int
+	ProfitPercent float64
+}
+
+// SolveRebalance computes the trade that moves reserve0/reserve1 to targetRatio.
+/*
+	Selling dx of a token with reserve x into a pool whose other reserve is y
+	leaves reserves x' = x + dx and y' = x*y / (x + g*dx), where g is the share of
+	the input left after the fee. Requiring x'/y' = t, the target expressed as
+	input reserve per output reserve, gives the quadratic
+
+		g*dx^2 + (1+g)*x*dx + x^2 - t*x*y = 0
+
+	whose positive root is the exact input. Token0 is sold when the pool ratio is
+	below target (t = targetRatio), token1 when it is above (t = 1/targetRatio).
+*/
+func SolveRebalance(reserve0, reserve1 *big.Int, targetRatio float64, feeBps uint64) (*Rebalance, error) {
+	if reserve0.Sign() <= 0 || reserve1.Sign() <= 0 {
+		return nil, ErrInsufficientLiquidity
+	}
+	if feeBps >= BpsDenominator {
+		return nil, ErrInvalidFee
+	}
+	if targetRatio <= 0 {
+		return nil, errors.New("target ratio must be positive")
+	}
+
+	target := newFloat().SetFloat64(targetRatio)
+	current := newFloat().Quo(newFloat().SetInt(reserve0), newFloat().SetInt(reserve1))
+
+	rebalance := &Rebalance{}
+	x, y, t := reserve0, reserve1, target
+	switch current.Cmp(target) {
+	case 0:
+		return nil, ErrBalanced
+	case 1:
+		// Too much token0 per token1: sell token1
+		x, y, t = reserve1, reserve0, newFloat().Quo(newFloat().SetInt64(1), target)
+	default:
+		rebalance.ZeroForOne = true
+	}
+
+	g := newFloat().Quo(newFloat().SetUint64(BpsDenominator-feeBps), newFloat().SetUint64(BpsDenominator))
+	xf := newFloat().SetInt(x)
+	yf := newFloat().SetInt(y)
+
+	// b = (1+g)*x
+	b := newFloat().Add(newFloat().SetInt64(1), g)
+	b.Mul(b, xf)
+
+	// c = x^2 - t*x*y
+	c := newFloat().Mul(xf, xf)
+	txy := newFloat().Mul(t, xf)
+	txy.Mul(txy, yf)
+	c.Sub(c, txy)
+
+	// dx = (-b + sqrt(b^2 - 4*g*c)) / (2*g)
+	discriminant := newFloat().Mul(b, b)
+	fourGC := newFloat().Mul(newFloat().SetInt64(4), g)
+	fourGC.Mul(fourGC, c)
+	discriminant.Sub(discriminant, fourGC)
+
+	dx := newFloat().Sqrt(discriminant)
+	dx.Sub(dx, b)
+	dx.Quo(dx, newFloat().Mul(newFloat().SetInt64(2), g))
+
+	amountIn, _ := dx.Int(nil)
+	if amountIn.Sign() <= 0 {
+		return nil, ErrBalanced
+	}
+
+	amountOut, err := GetAmountOut(amountIn, x, y, feeBps)
+	if err != nil {
+		return nil, err
+	}
+
+	// Value the output in input-token units at the target: t is input per output
+	value := newFloat().SetInt(amountOut)
+	value.Mul(value, t)
+	profit := value.Sub(value, newFloat().SetInt(amountIn))
+
+	rebalance.AmountIn = amountIn
+	rebalance.AmountOut = amountOut
+	rebalance.Profit, _ = profit.Int(nil)
+
+	percent := newFloat().Quo(profit, newFloat().SetInt(amountIn))
+	rebalance.ProfitPercent, _ = percent.Float64()
+	rebalance.ProfitPercent *= 100
+
+	return rebalance, nil
+}
+
+// newFloat returns a zero big.Float at the solver precision
+func newFloat() *big.Float {
+	return new(big.Float).SetPrec(floatPrec)
+}
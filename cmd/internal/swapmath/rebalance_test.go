@@ -0,0 +1,69 @@
+package swapmath
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestSolveRebalance(t *testing.T) {
+	tests := []struct {
+		name               string
+		reserve0, reserve1 string
+		target             float64
+		zeroForOne         bool
+		profitable         bool
+	}{
+		{"below target sells token0", "1000000000000000000000", "1000000000000000000000", 1.2, true, true},
+		{"above target sells token1", "1500000000000000000000", "1000000000000000000000", 1.2, false, true},
+		{"unequal decimals", "920000000", "1000000000000000000000", 0.000000000001, true, true},
+		{"inside the fee", "1000000000000000000000", "1000000000000000000000", 1.001, true, false},
+		{"far above target", "5000000000000000000000", "1000000000000000000000", 0.5, false, true},
+	}
+	for _, test := range tests {
+		reserve0, reserve1 := number(t, test.reserve0), number(t, test.reserve1)
+		rebalance, err := SolveRebalance(reserve0, reserve1, test.target, 30)
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if rebalance.ZeroForOne != test.zeroForOne {
+			t.Errorf("%s: ZeroForOne = %t, want %t", test.name, rebalance.ZeroForOne, test.zeroForOne)
+		}
+		if profitable := rebalance.Profit.Sign() > 0; profitable != test.profitable {
+			t.Errorf("%s: profit %s, want profitable %t", test.name, rebalance.Profit, test.profitable)
+		}
+
+		// Trading AmountIn through the pool lands on the target ratio
+		want, _ := GetAmountOut(rebalance.AmountIn, reserve0, reserve1, 30)
+		after0, after1 := new(big.Int).Add(reserve0, rebalance.AmountIn), new(big.Int).Sub(reserve1, rebalance.AmountOut)
+		if !rebalance.ZeroForOne {
+			want, _ = GetAmountOut(rebalance.AmountIn, reserve1, reserve0, 30)
+			after0, after1 = new(big.Int).Sub(reserve0, rebalance.AmountOut), new(big.Int).Add(reserve1, rebalance.AmountIn)
+		}
+		if rebalance.AmountOut.Cmp(want) != 0 {
+			t.Errorf("%s: AmountOut = %s, want %s", test.name, rebalance.AmountOut, want)
+		}
+		ratio, _ := new(big.Float).Quo(new(big.Float).SetInt(after0), new(big.Float).SetInt(after1)).Float64()
+		if math.Abs(ratio/test.target-1) > 1e-9 {
+			t.Errorf("%s: ratio after the trade = %v, want %v", test.name, ratio, test.target)
+		}
+	}
+}
+
+func TestSolveRebalanceErrors(t *testing.T) {
+	reserve := number(t, "1000000000000000000000")
+	if _, err := SolveRebalance(reserve, reserve, 1, 30); !errors.Is(err, ErrBalanced) {
+		t.Errorf("balanced pool: error = %v, want %v", err, ErrBalanced)
+	}
+	if _, err := SolveRebalance(reserve, big.NewInt(0), 1, 30); !errors.Is(err, ErrInsufficientLiquidity) {
+		t.Errorf("empty pool: error = %v, want %v", err, ErrInsufficientLiquidity)
+	}
+	if _, err := SolveRebalance(reserve, reserve, 1.2, BpsDenominator); !errors.Is(err, ErrInvalidFee) {
+		t.Errorf("100%% fee: error = %v, want %v", err, ErrInvalidFee)
+	}
+	if _, err := SolveRebalance(reserve, reserve, 0, 30); err == nil {
+		t.Error("a zero target was accepted")
+	}
+}
@@ -0,0 +1,105 @@
+// Package swapmath implements the constant-product swap formulas of
+// UniswapV2Library in exact integer arithmetic, with the pool fee given in
+// basis points (30 for the standard 0.3% Uniswap V2 fee).
+package swapmath
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// BpsDenominator is the number of basis points in 100%
+const BpsDenominator = 10000
+
+// Errors mirror the revert reasons of UniswapV2Library
+var (
+	ErrInsufficientInputAmount  = errors.New("INSUFFICIENT_INPUT_AMOUNT")
+	ErrInsufficientOutputAmount = errors.New("INSUFFICIENT_OUTPUT_AMOUNT")
+	ErrInsufficientLiquidity    = errors.New("INSUFFICIENT_LIQUIDITY")
+	ErrInvalidFee               = errors.New("fee must be below 10000 bps")
+)
+
+// Hop is one pool along a swap path, oriented in the direction of the trade
+type Hop struct {
+	ReserveIn  *big.Int
+	ReserveOut *big.Int
+	FeeBps     uint64
+}
+
+// GetAmountOut returns the maximum output for amountIn given the pool reserves,
+// exactly as UniswapV2Library.getAmountOut does with a 997/1000 fee
+func GetAmountOut(amountIn, reserveIn, reserveOut *big.Int, feeBps uint64) (*big.Int, error) {
+	if amountIn.Sign() <= 0 {
+		return nil, ErrInsufficientInputAmount
+	}
+	if reserveIn.Sign() <= 0 || reserveOut.Sign() <= 0 {
+		return nil, ErrInsufficientLiquidity
+	}
+	if feeBps >= BpsDenominator {
+		return nil, ErrInvalidFee
+	}
+
+	amountInWithFee := new(big.Int).Mul(amountIn, big.NewInt(int64(BpsDenominator-feeBps)))
+	numerator := new(big.Int).Mul(amountInWithFee, reserveOut)
+	denominator := new(big.Int).Mul(reserveIn, big.NewInt(BpsDenominator))
+	denominator.Add(denominator, amountInWithFee)
+
+	return numerator.Quo(numerator, denominator), nil
+}
+
+// GetAmountIn returns the minimum input needed to receive amountOut given the pool
+// reserves, exactly as UniswapV2Library.getAmountIn does (rounding up)
+func GetAmountIn(amountOut, reserveIn, reserveOut *big.Int, feeBps uint64) (*big.Int, error) {
+	if amountOut.Sign() <= 0 {
+		return nil, ErrInsufficientOutputAmount
+	}
+	if reserveIn.Sign() <= 0 || reserveOut.Sign() <= 0 || amountOut.Cmp(reserveOut) >= 0 {
+		return nil, ErrInsufficientLiquidity
+	}
+	if feeBps >= BpsDenominator {
+		return nil, ErrInvalidFee
+	}
+
+	numerator := new(big.Int).Mul(reserveIn, amountOut)
+	numerator.Mul(numerator, big.NewInt(BpsDenominator))
+	denominator := new(big.Int).Sub(reserveOut, amountOut)
+	denominator.Mul(denominator, big.NewInt(int64(BpsDenominator-feeBps)))
+
+	amountIn := numerator.Quo(numerator, denominator)
+	return amountIn.Add(amountIn, big.NewInt(1)), nil
+}
+
+// GetAmountsOut chains GetAmountOut through every hop of a path.
+// The result starts with amountIn and has one entry per hop after it.
+func GetAmountsOut(amountIn *big.Int, hops []Hop) ([]*big.Int, error) {
+	amounts := make([]*big.Int, len(hops)+1)
+	amounts[0] = new(big.Int).Set(amountIn)
+
+	for i, hop := range hops {
+		out, err := GetAmountOut(amounts[i], hop.ReserveIn, hop.ReserveOut, hop.FeeBps)
+		if err != nil {
+			return nil, fmt.Errorf("hop %d: %w", i, err)
+		}
+		amounts[i+1] = out
+	}
+
+	return amounts, nil
+}
+
+// GetAmountsIn chains GetAmountIn backwards through every hop of a path.
+// The result ends with amountOut and has one entry per hop before it.
+func GetAmountsIn(amountOut *big.Int, hops []Hop) ([]*big.Int, error) {
+	amounts := make([]*big.Int, len(hops)+1)
+	amounts[len(hops)] = new(big.Int).Set(amountOut)
+
+	for i := len(hops) - 1; i >= 0; i-- {
+		in, err := GetAmountIn(amounts[i+1], hops[i].ReserveIn, hops[i].ReserveOut, hops[i].FeeBps)
+		if err != nil {
+			return nil, fmt.Errorf("hop %d: %w", i, err)
+		}
+		amounts[i] = in
+	}
+
+	return amounts, nil
+}
@@ -0,0 +1,134 @@
+package swapmath
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+// number parses a decimal string, so the expected values can be written out in full
+func number(t *testing.T, s string) *big.Int {
+	t.Helper()
+
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		t.Fatalf("bad number %q", s)
+	}
+	return n
+}
+
+// Expected values follow UniswapV2Library: getAmountOut multiplies by 997/1000
+// and getAmountIn adds one to the floored quotient
+func TestGetAmountOut(t *testing.T) {
+	tests := []struct {
+		name                            string
+		amountIn, reserveIn, reserveOut string
+		feeBps                          uint64
+		want                            string
+	}{
+		{"standard fee", "1000000000000000000", "100000000000000000000", "200000000000000000000", 30, "1974316068794122597"},
+		{"rounds down", "1000", "1000", "1000", 30, "499"},
+		{"dust", "1", "1000", "1000", 30, "0"},
+		{"no fee", "1000000", "1000000000000", "1000000000000", 0, "999999"},
+		{"1% fee", "1000000", "1000000000000", "1000000000000", 100, "989999"},
+	}
+	for _, test := range tests {
+		got, err := GetAmountOut(number(t, test.amountIn), number(t, test.reserveIn), number(t, test.reserveOut), test.feeBps)
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if got.Cmp(number(t, test.want)) != 0 {
+			t.Errorf("%s: GetAmountOut = %s, want %s", test.name, got, test.want)
+		}
+	}
+}
+
+func TestGetAmountIn(t *testing.T) {
+	tests := []struct {
+		name                             string
+		amountOut, reserveIn, reserveOut string
+		want                             string
+	}{
+		{"standard fee", "1000000000000000000", "100000000000000000000", "200000000000000000000", "504024636724243082"},
+		{"rounds up", "500", "1000", "1000", "1004"},
+		{"smallest output", "1", "1000000", "1000000", "2"},
+	}
+	for _, test := range tests {
+		reserveIn, reserveOut := number(t, test.reserveIn), number(t, test.reserveOut)
+		got, err := GetAmountIn(number(t, test.amountOut), reserveIn, reserveOut, 30)
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if got.Cmp(number(t, test.want)) != 0 {
+			t.Errorf("%s: GetAmountIn = %s, want %s", test.name, got, test.want)
+		}
+
+		// The input is the smallest one that buys the output
+		out, _ := GetAmountOut(got, reserveIn, reserveOut, 30)
+		short, _ := GetAmountOut(new(big.Int).Sub(got, big.NewInt(1)), reserveIn, reserveOut, 30)
+		if out.Cmp(number(t, test.amountOut)) < 0 || short.Cmp(number(t, test.amountOut)) >= 0 {
+			t.Errorf("%s: %s is not the smallest input for %s", test.name, got, test.amountOut)
+		}
+	}
+}
+
+func TestAmountErrors(t *testing.T) {
+	one, zero := big.NewInt(1), big.NewInt(0)
+	reserve := big.NewInt(1000)
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"out: zero input", second(GetAmountOut(zero, reserve, reserve, 30)), ErrInsufficientInputAmount},
+		{"out: empty pool", second(GetAmountOut(one, zero, reserve, 30)), ErrInsufficientLiquidity},
+		{"out: 100% fee", second(GetAmountOut(one, reserve, reserve, BpsDenominator)), ErrInvalidFee},
+		{"in: zero output", second(GetAmountIn(zero, reserve, reserve, 30)), ErrInsufficientOutputAmount},
+		{"in: whole reserve", second(GetAmountIn(reserve, reserve, reserve, 30)), ErrInsufficientLiquidity},
+		{"in: 100% fee", second(GetAmountIn(one, reserve, reserve, BpsDenominator)), ErrInvalidFee},
+	}
+	for _, test := range tests {
+		if !errors.Is(test.err, test.want) {
+			t.Errorf("%s: error = %v, want %v", test.name, test.err, test.want)
+		}
+	}
+}
+
+func TestGetAmountsOutAndIn(t *testing.T) {
+	hops := []Hop{
+		{ReserveIn: number(t, "1000000000000000000000"), ReserveOut: number(t, "2000000000000000000000"), FeeBps: 30},
+		{ReserveIn: number(t, "500000000000000000000"), ReserveOut: number(t, "250000000000000000000"), FeeBps: 30},
+	}
+	want := []string{"10000000000000000000", "19743160687941225977", "9469184067420894638"}
+
+	amounts, err := GetAmountsOut(number(t, want[0]), hops)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range want {
+		if amounts[i].Cmp(number(t, want[i])) != 0 {
+			t.Errorf("GetAmountsOut[%d] = %s, want %s", i, amounts[i], want[i])
+		}
+	}
+
+	// Going backwards from the output never asks for more than was put in
+	back, err := GetAmountsIn(amounts[len(amounts)-1], hops)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if back[len(back)-1].Cmp(amounts[len(amounts)-1]) != 0 || back[0].Cmp(amounts[0]) > 0 {
+		t.Errorf("GetAmountsIn = %v for amounts %v", back, amounts)
+	}
+
+	empty := Hop{ReserveIn: big.NewInt(0), ReserveOut: big.NewInt(1), FeeBps: 30}
+	if _, err := GetAmountsOut(amounts[0], []Hop{hops[0], empty}); !errors.Is(err, ErrInsufficientLiquidity) {
+		t.Errorf("GetAmountsOut through an empty pool: error = %v", err)
+	}
+}
+
+// second returns the error of a two-value call
+func second(_ *big.Int, err error) error {
+	return err
+}
@@ -0,0 +1,233 @@
+package testchain
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// variable is a word of memory a contract keeps a value in
+type variable int
+
+// Memory layout shared by the mock contracts: variables from 0x80, outgoing
+// calldata at callIn, return data of calls at callOut and the contract's own
+// dynamic output from output
+const (
+	callIn  = 0x400
+	callOut = 0x500
+	output  = 0x1000
+)
+
+// assembler builds EVM bytecode. Jumps go to named labels, which can be
+// used before they are placed.
+type assembler struct {
+	code   []byte
+	labels map[string]int
+	refs   map[int]string // position of each label push, by label
+}
+
+func newAssembler() *assembler {
+	return &assembler{labels: make(map[string]int), refs: make(map[int]string)}
+}
+
+// op appends opcodes
+func (a *assembler) op(ops ...vm.OpCode) {
+	for _, op := range ops {
+		a.code = append(a.code, byte(op))
+	}
+}
+
+// put pushes a value: a variable's content, the result of an opcode such as
+// vm.CALLER, or a constant (int, *big.Int, common.Address or up to 32 bytes)
+func (a *assembler) put(value any) {
+	var data []byte
+	switch value := value.(type) {
+	case variable:
+		a.put(int(value))
+		a.op(vm.MLOAD)
+		return
+	case vm.OpCode:
+		a.op(value)
+		return
+	case int:
+		data = big.NewInt(int64(value)).Bytes()
+	case *big.Int:
+		data = value.Bytes()
+	case common.Address:
+		data = value.Bytes()
+	case []byte:
+		data = value
+	default:
+		panic(fmt.Sprintf("cannot push %T", value))
+	}
+	if len(data) == 0 {
+		data = []byte{0}
+	}
+
+	a.code = append(a.code, byte(vm.PUSH1)+byte(len(data)-1))
+	a.code = append(a.code, data...)
+}
+
+// store pops the top of the stack into a variable
+func (a *assembler) store(v variable) {
+	a.put(int(v))
+	a.op(vm.MSTORE)
+}
+
+// arg pushes the i-th static argument of the call
+func (a *assembler) arg(i int) {
+	a.put(4 + 32*i)
+	a.op(vm.CALLDATALOAD)
+}
+
+// label places a jump destination
+func (a *assembler) label(name string) {
+	if _, ok := a.labels[name]; ok {
+		panic("label " + name + " placed twice")
+	}
+	a.labels[name] = len(a.code)
+	a.op(vm.JUMPDEST)
+}
+
+// pushLabel pushes a label's position, filled in by bytes
+func (a *assembler) pushLabel(name string) {
+	a.refs[len(a.code)] = name
+	a.code = append(a.code, byte(vm.PUSH2), 0, 0)
+}
+
+// jump jumps to a label
+func (a *assembler) jump(name string) {
+	a.pushLabel(name)
+	a.op(vm.JUMP)
+}
+
+// jumpIf pops the top of the stack and jumps to a label when it is not zero
+func (a *assembler) jumpIf(name string) {
+	a.pushLabel(name)
+	a.op(vm.JUMPI)
+}
+
+// selector jumps to a label when the call is to the given function. The
+// selector stays on the stack.
+func (a *assembler) selector(signature, name string) {
+	a.op(vm.DUP1)
+	a.put(crypto.Keccak256([]byte(signature))[:4])
+	a.op(vm.EQ)
+	a.jumpIf(name)
+}
+
+// dispatch pushes the selector of the call, for selector to compare
+func (a *assembler) dispatch() {
+	a.put(0)
+	a.op(vm.CALLDATALOAD)
+	a.put(224)
+	a.op(vm.SHR)
+}
+
+// encode writes the calldata of a call at callIn. Arguments are pushed as by put.
+func (a *assembler) encode(signature string, args ...any) {
+	a.put(common.RightPadBytes(crypto.Keccak256([]byte(signature))[:4], 32))
+	a.put(callIn)
+	a.op(vm.MSTORE)
+	for i, arg := range args {
+		a.put(arg)
+		a.put(callIn + 4 + 32*i)
+		a.op(vm.MSTORE)
+	}
+}
+
+// call calls target with the calldata encode wrote for args arguments, copying
+// up to outWords words of return data to callOut. A failed call reverts with its
+// revert data, through the "bubble" label the contract has to place.
+func (a *assembler) call(call vm.OpCode, target any, args, outWords int) {
+	a.put(32 * outWords)
+	a.put(callOut)
+	a.put(4 + 32*args)
+	a.put(callIn)
+	if call == vm.CALL {
+		a.put(0) // value
+	}
+	a.put(target)
+	a.op(vm.GAS, call, vm.ISZERO)
+	a.jumpIf("bubble")
+}
+
+// bubble places the "bubble" label, reverting with the return data of the last call
+func (a *assembler) bubble() {
+	a.label("bubble")
+	a.op(vm.RETURNDATASIZE)
+	a.put(0)
+	a.put(0)
+	a.op(vm.RETURNDATACOPY)
+	a.op(vm.RETURNDATASIZE)
+	a.put(0)
+	a.op(vm.REVERT)
+}
+
+// returnWord returns the top of the stack as a single word
+func (a *assembler) returnWord() {
+	a.put(0)
+	a.op(vm.MSTORE)
+	a.put(32)
+	a.put(0)
+	a.op(vm.RETURN)
+}
+
+// returnString returns an ABI-encoded string
+func (a *assembler) returnString(s string) {
+	words := (len(s) + 31) / 32
+	a.put(0x20)
+	a.put(0)
+	a.op(vm.MSTORE)
+	a.put(len(s))
+	a.put(0x20)
+	a.op(vm.MSTORE)
+	for i := 0; i < words; i++ {
+		a.put(common.RightPadBytes([]byte(s[32*i:min(32*(i+1), len(s))]), 32))
+		a.put(0x40 + 32*i)
+		a.op(vm.MSTORE)
+	}
+	a.put(0x40 + 32*words)
+	a.put(0)
+	a.op(vm.RETURN)
+}
+
+// revert places a label reverting with an Error(string) reason
+func (a *assembler) revert(name, reason string) {
+	a.label(name)
+
+	words := (len(reason) + 31) / 32
+	a.put(common.RightPadBytes(crypto.Keccak256([]byte("Error(string)"))[:4], 32))
+	a.put(0)
+	a.op(vm.MSTORE)
+	a.put(0x20)
+	a.put(4)
+	a.op(vm.MSTORE)
+	a.put(len(reason))
+	a.put(0x24)
+	a.op(vm.MSTORE)
+	for i := 0; i < words; i++ {
+		a.put(common.RightPadBytes([]byte(reason[32*i:min(32*(i+1), len(reason))]), 32))
+		a.put(0x44 + 32*i)
+		a.op(vm.MSTORE)
+	}
+	a.put(0x44 + 32*words)
+	a.put(0)
+	a.op(vm.REVERT)
+}
+
+// bytes returns the code with every label position filled in
+func (a *assembler) bytes() []byte {
+	for at, name := range a.refs {
+		position, ok := a.labels[name]
+		if !ok {
+			panic("label " + name + " is never placed")
+		}
+		a.code[at+1], a.code[at+2] = byte(position>>8), byte(position)
+	}
+
+	return a.code
+}
@@ -0,0 +1,701 @@
+package testchain
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Events the mock contracts emit, with the signatures of the real ones
+var (
+	transferTopic = crypto.Keccak256([]byte("Transfer(address,address,uint256)"))
+	syncTopic     = crypto.Keccak256([]byte("Sync(uint112,uint112)"))
+	swapTopic     = crypto.Keccak256([]byte("Swap(address,uint256,uint256,uint256,uint256,address)"))
+)
+
+// balanceSlot is the storage slot of an account's token balance
+func balanceSlot(account common.Address) common.Hash {
+	return common.BytesToHash(account.Bytes())
+}
+
+// routerSlot is the storage slot of the router's pair for two tokens, in either order
+func routerSlot(tokenA, tokenB common.Address) common.Hash {
+	var slot common.Hash
+	for i := range tokenA {
+		slot[12+i] = tokenA[i] ^ tokenB[i]
+	}
+
+	return slot
+}
+
+// tokenCode is an ERC-20 keeping balances at the slot of the account's
+// address and allowances at keccak256(owner, spender)
+func tokenCode(symbol string, decimals uint8) []byte {
+	const (
+		from    variable = 0x80
+		to      variable = 0xa0
+		amount  variable = 0xc0
+		balance variable = 0xe0
+	)
+
+	a := newAssembler()
+	a.dispatch()
+	a.selector("symbol()", "symbol")
+	a.selector("decimals()", "decimals")
+	a.selector("balanceOf(address)", "balanceOf")
+	a.selector("allowance(address,address)", "allowance")
+	a.selector("approve(address,uint256)", "approve")
+	a.selector("transfer(address,uint256)", "transfer")
+	a.selector("transferFrom(address,address,uint256)", "transferFrom")
+	a.revert("unknown", "")
+
+	a.label("symbol")
+	a.returnString(symbol)
+
+	a.label("decimals")
+	a.put(int(decimals))
+	a.returnWord()
+
+	a.label("balanceOf")
+	a.arg(0)
+	a.op(vm.SLOAD)
+	a.returnWord()
+
+	// allowanceSlot replaces owner and spender on the stack with their allowance slot
+	allowanceSlot := func() {
+		a.put(0x20)
+		a.op(vm.MSTORE)
+		a.put(0)
+		a.op(vm.MSTORE)
+		a.put(0x40)
+		a.put(0)
+		a.op(vm.KECCAK256)
+	}
+
+	a.label("allowance")
+	a.arg(0)
+	a.arg(1)
+	allowanceSlot()
+	a.op(vm.SLOAD)
+	a.returnWord()
+
+	a.label("approve")
+	a.arg(1)
+	a.put(vm.CALLER)
+	a.arg(0)
+	allowanceSlot()
+	a.op(vm.SSTORE)
+	a.put(1)
+	a.returnWord()
+
+	a.label("transfer")
+	a.put(vm.CALLER)
+	a.store(from)
+	a.arg(0)
+	a.store(to)
+	a.arg(1)
+	a.store(amount)
+	a.jump("move")
+
+	a.label("transferFrom")
+	a.arg(0)
+	a.store(from)
+	a.arg(1)
+	a.store(to)
+	a.arg(2)
+	a.store(amount)
+	a.put(from)
+	a.put(vm.CALLER)
+	allowanceSlot()
+	a.op(vm.DUP1, vm.SLOAD) // slot, allowed
+	a.op(vm.DUP1)
+	a.put(amount)
+	a.op(vm.GT)
+	a.jumpIf("insufficientAllowance")
+	a.put(amount)
+	a.op(vm.SWAP1, vm.SUB, vm.SWAP1, vm.SSTORE)
+
+	a.label("move")
+	a.put(from)
+	a.op(vm.SLOAD)
+	a.store(balance)
+	a.put(balance)
+	a.put(amount)
+	a.op(vm.GT)
+	a.jumpIf("insufficientBalance")
+	a.put(amount)
+	a.put(balance)
+	a.op(vm.SUB)
+	a.put(from)
+	a.op(vm.SSTORE)
+	a.put(to)
+	a.op(vm.SLOAD)
+	a.put(amount)
+	a.op(vm.ADD)
+	a.put(to)
+	a.op(vm.SSTORE)
+	a.put(amount)
+	a.put(0)
+	a.op(vm.MSTORE)
+	a.put(to)
+	a.put(from)
+	a.put(transferTopic)
+	a.put(32)
+	a.put(0)
+	a.op(vm.LOG3)
+	a.put(1)
+	a.returnWord()
+
+	a.revert("insufficientAllowance", "ERC20: insufficient allowance")
+	a.revert("insufficientBalance", "ERC20: transfer amount exceeds balance")
+
+	return a.bytes()
+}
+
+// pairCode is a Uniswap V2 pair of two tokens keeping reserve0, reserve1 and
+// blockTimestampLast in slots 0 to 2. swap and sync behave as the real pair's,
+// including the 0.3% fee in the constant product check.
+func pairCode(token0, token1 common.Address) []byte {
+	const (
+		out0     variable = 0x80
+		out1     variable = 0xa0
+		to       variable = 0xc0
+		balance0 variable = 0xe0
+		balance1 variable = 0x100
+		in0      variable = 0x120
+		in1      variable = 0x140
+		reserve0 variable = 0x160
+		reserve1 variable = 0x180
+	)
+
+	a := newAssembler()
+	a.dispatch()
+	a.selector("token0()", "token0")
+	a.selector("token1()", "token1")
+	a.selector("getReserves()", "getReserves")
+	a.selector("swap(uint256,uint256,address,bytes)", "swap")
+	a.selector("sync()", "sync")
+	a.revert("unknown", "")
+
+	a.label("token0")
+	a.put(token0)
+	a.returnWord()
+
+	a.label("token1")
+	a.put(token1)
+	a.returnWord()
+
+	a.label("getReserves")
+	for slot := 0; slot < 3; slot++ {
+		a.put(slot)
+		a.op(vm.SLOAD)
+		a.put(32 * slot)
+		a.op(vm.MSTORE)
+	}
+	a.put(0x60)
+	a.put(0)
+	a.op(vm.RETURN)
+
+	// update stores the token balances as the reserves and emits Sync
+	update := func() {
+		for i, token := range []common.Address{token0, token1} {
+			a.encode("balanceOf(address)", vm.ADDRESS)
+			a.call(vm.STATICCALL, token, 1, 1)
+			a.put(callOut)
+			a.op(vm.MLOAD)
+			a.store([]variable{balance0, balance1}[i])
+		}
+		a.put(balance0)
+		a.put(0)
+		a.op(vm.SSTORE)
+		a.put(balance1)
+		a.put(1)
+		a.op(vm.SSTORE)
+		a.put(vm.TIMESTAMP)
+		a.put(2)
+		a.op(vm.SSTORE)
+
+		a.put(balance0)
+		a.put(0)
+		a.op(vm.MSTORE)
+		a.put(balance1)
+		a.put(0x20)
+		a.op(vm.MSTORE)
+		a.put(syncTopic)
+		a.put(0x40)
+		a.put(0)
+		a.op(vm.LOG1)
+	}
+
+	a.label("sync")
+	update()
+	a.op(vm.STOP)
+
+	a.label("swap")
+	a.put(0)
+	a.op(vm.SLOAD)
+	a.store(reserve0)
+	a.put(1)
+	a.op(vm.SLOAD)
+	a.store(reserve1)
+	a.arg(0)
+	a.store(out0)
+	a.arg(1)
+	a.store(out1)
+	a.arg(2)
+	a.store(to)
+
+	a.put(out0)
+	a.put(out1)
+	a.op(vm.OR, vm.ISZERO)
+	a.jumpIf("insufficientOutput")
+	a.put(reserve0)
+	a.put(out0)
+	a.op(vm.LT)
+	a.put(reserve1)
+	a.put(out1)
+	a.op(vm.LT, vm.AND, vm.ISZERO)
+	a.jumpIf("insufficientLiquidity")
+
+	// Send the outputs first, then work out what was paid in from the balances
+	for i, token := range []common.Address{token0, token1} {
+		out := []variable{out0, out1}[i]
+		skip := []string{"sent0", "sent1"}[i]
+		a.put(out)
+		a.op(vm.ISZERO)
+		a.jumpIf(skip)
+		a.encode("transfer(address,uint256)", to, out)
+		a.call(vm.CALL, token, 2, 1)
+		a.label(skip)
+	}
+	update()
+
+	// amountIn = balance - (reserve - amountOut) when the balance is larger, else 0
+	for i := range 2 {
+		in, out := []variable{in0, in1}[i], []variable{out0, out1}[i]
+		balance, reserve := []variable{balance0, balance1}[i], []variable{reserve0, reserve1}[i]
+		none, done := []string{"noIn0", "noIn1"}[i], []string{"in0", "in1"}[i]
+		a.put(out)
+		a.put(reserve)
+		a.op(vm.SUB)
+		a.store(in)
+		a.put(in)
+		a.put(balance)
+		a.op(vm.GT, vm.ISZERO)
+		a.jumpIf(none)
+		a.put(in)
+		a.put(balance)
+		a.op(vm.SUB)
+		a.store(in)
+		a.jump(done)
+		a.label(none)
+		a.put(0)
+		a.store(in)
+		a.label(done)
+	}
+	a.put(in0)
+	a.put(in1)
+	a.op(vm.OR, vm.ISZERO)
+	a.jumpIf("insufficientInput")
+
+	// (balance0×1000 − in0×3) × (balance1×1000 − in1×3) ≥ reserve0 × reserve1 × 1000²
+	for i := range 2 {
+		a.put([]variable{in0, in1}[i])
+		a.put(3)
+		a.op(vm.MUL)
+		a.put(1000)
+		a.put([]variable{balance0, balance1}[i])
+		a.op(vm.MUL, vm.SUB)
+	}
+	a.op(vm.MUL)
+	a.put(reserve1)
+	a.put(reserve0)
+	a.op(vm.MUL)
+	a.put(1_000_000)
+	a.op(vm.MUL, vm.GT)
+	a.jumpIf("k")
+
+	for i, v := range []variable{in0, in1, out0, out1} {
+		a.put(v)
+		a.put(32 * i)
+		a.op(vm.MSTORE)
+	}
+	a.put(to)
+	a.put(vm.CALLER)
+	a.put(swapTopic)
+	a.put(0x80)
+	a.put(0)
+	a.op(vm.LOG3)
+	a.op(vm.STOP)
+
+	a.revert("insufficientOutput", "UniswapV2: INSUFFICIENT_OUTPUT_AMOUNT")
+	a.revert("insufficientLiquidity", "UniswapV2: INSUFFICIENT_LIQUIDITY")
+	a.revert("insufficientInput", "UniswapV2: INSUFFICIENT_INPUT_AMOUNT")
+	a.revert("k", "UniswapV2: K")
+	a.bubble()
+
+	return a.bytes()
+}
+
+// routerCode is a Uniswap V2 router supporting swapExactTokensForTokens, with
+// the pair of two tokens stored at routerSlot
+func routerCode(weth common.Address) []byte {
+	const (
+		amountIn   variable = 0x80
+		minOut     variable = 0xa0
+		path       variable = 0xc0 // calldata position of path[0]
+		n          variable = 0xe0
+		to         variable = 0x100
+		i          variable = 0x120
+		from       variable = 0x140
+		next       variable = 0x160
+		pair       variable = 0x180
+		reserveIn  variable = 0x1a0
+		reserveOut variable = 0x1c0
+		token0     variable = 0x1e0
+		amount     variable = 0x200
+		recipient  variable = 0x220
+		out0       variable = 0x240
+		out1       variable = 0x260
+		withFee    variable = 0x280
+	)
+
+	a := newAssembler()
+	a.dispatch()
+	a.selector("WETH()", "weth")
+	a.selector("swapExactTokensForTokens(uint256,uint256,address[],address,uint256)", "swap")
+	a.revert("unknown", "")
+
+	a.label("weth")
+	a.put(weth)
+	a.returnWord()
+
+	// pathAt pushes path[i + offset]
+	pathAt := func(offset int) {
+		a.put(i)
+		a.put(offset)
+		a.op(vm.ADD)
+		a.put(5)
+		a.op(vm.SHL)
+		a.put(path)
+		a.op(vm.ADD, vm.CALLDATALOAD)
+	}
+	// amountAt pushes the memory position of amounts[i + offset]
+	amountAt := func(offset int) {
+		a.put(i)
+		a.put(5)
+		a.op(vm.SHL)
+		a.put(output + 0x40 + 32*offset)
+		a.op(vm.ADD)
+	}
+	// hop loads the tokens of step i, their pair and its token0
+	hop := func() {
+		pathAt(0)
+		a.store(from)
+		pathAt(1)
+		a.store(next)
+		a.put(from)
+		a.put(next)
+		a.op(vm.XOR, vm.SLOAD, vm.DUP1)
+		a.store(pair)
+		a.op(vm.ISZERO)
+		a.jumpIf("noPair")
+		a.encode("token0()")
+		a.call(vm.STATICCALL, pair, 0, 1)
+		a.put(callOut)
+		a.op(vm.MLOAD)
+		a.store(token0)
+	}
+	// whileHops jumps to a label once i+1 reaches the path length
+	whileHops := func(done string) {
+		a.put(n)
+		a.put(i)
+		a.put(1)
+		a.op(vm.ADD, vm.LT, vm.ISZERO)
+		a.jumpIf(done)
+	}
+
+	a.label("swap")
+	a.arg(4)
+	a.put(vm.TIMESTAMP)
+	a.op(vm.GT)
+	a.jumpIf("expired")
+	a.arg(0)
+	a.store(amountIn)
+	a.arg(1)
+	a.store(minOut)
+	a.arg(3)
+	a.store(to)
+	a.arg(2)
+	a.put(4)
+	a.op(vm.ADD, vm.DUP1, vm.CALLDATALOAD)
+	a.store(n)
+	a.put(32)
+	a.op(vm.ADD)
+	a.store(path)
+	a.put(n)
+	a.put(2)
+	a.op(vm.GT)
+	a.jumpIf("invalidPath")
+
+	// Quote every hop into amounts, ABI-encoded at output
+	a.put(0x20)
+	a.put(output)
+	a.op(vm.MSTORE)
+	a.put(n)
+	a.put(output + 0x20)
+	a.op(vm.MSTORE)
+	a.put(amountIn)
+	a.put(output + 0x40)
+	a.op(vm.MSTORE)
+	a.put(0)
+	a.store(i)
+	a.label("quote")
+	whileHops("quoted")
+	hop()
+	a.encode("getReserves()")
+	a.call(vm.STATICCALL, pair, 0, 3)
+	a.put(callOut)
+	a.op(vm.MLOAD)
+	a.store(reserveIn)
+	a.put(callOut + 0x20)
+	a.op(vm.MLOAD)
+	a.store(reserveOut)
+	a.put(from)
+	a.put(token0)
+	a.op(vm.EQ)
+	a.jumpIf("oriented")
+	a.put(reserveOut)
+	a.put(reserveIn)
+	a.store(reserveOut)
+	a.store(reserveIn)
+	a.label("oriented")
+
+	// amountOut = amount×997×reserveOut / (reserveIn×1000 + amount×997)
+	amountAt(0)
+	a.op(vm.MLOAD)
+	a.put(997)
+	a.op(vm.MUL)
+	a.store(withFee)
+	a.put(withFee)
+	a.put(reserveIn)
+	a.put(1000)
+	a.op(vm.MUL, vm.ADD)
+	a.put(reserveOut)
+	a.put(withFee)
+	a.op(vm.MUL, vm.DIV)
+	amountAt(1)
+	a.op(vm.MSTORE)
+	a.put(i)
+	a.put(1)
+	a.op(vm.ADD)
+	a.store(i)
+	a.jump("quote")
+
+	a.label("quoted")
+	a.put(n)
+	a.put(5)
+	a.op(vm.SHL)
+	a.put(output + 0x20)
+	a.op(vm.ADD, vm.MLOAD)
+	a.put(minOut)
+	a.op(vm.GT)
+	a.jumpIf("insufficientOutput")
+
+	// Pay the first pair, then swap along the path, each pair sending to the next
+	a.put(0)
+	a.store(i)
+	hop()
+	a.encode("transferFrom(address,address,uint256)", vm.CALLER, pair, amountIn)
+	a.call(vm.CALL, from, 3, 1)
+
+	a.label("step")
+	whileHops("swapped")
+	hop()
+	amountAt(1)
+	a.op(vm.MLOAD)
+	a.store(amount)
+	a.put(to)
+	a.store(recipient)
+	a.put(n)
+	a.put(i)
+	a.put(2)
+	a.op(vm.ADD, vm.LT, vm.ISZERO)
+	a.jumpIf("last")
+	pathAt(2)
+	a.put(next)
+	a.op(vm.XOR, vm.SLOAD)
+	a.store(recipient)
+	a.label("last")
+	a.put(0)
+	a.store(out0)
+	a.put(amount)
+	a.store(out1)
+	a.put(from)
+	a.put(token0)
+	a.op(vm.EQ)
+	a.jumpIf("zeroForOne")
+	a.put(amount)
+	a.store(out0)
+	a.put(0)
+	a.store(out1)
+	a.label("zeroForOne")
+	a.encode("swap(uint256,uint256,address,bytes)", out0, out1, recipient, 0x80, 0)
+	a.call(vm.CALL, pair, 5, 0)
+	a.put(i)
+	a.put(1)
+	a.op(vm.ADD)
+	a.store(i)
+	a.jump("step")
+
+	a.label("swapped")
+	a.put(n)
+	a.put(5)
+	a.op(vm.SHL)
+	a.put(0x40)
+	a.op(vm.ADD)
+	a.put(output)
+	a.op(vm.RETURN)
+
+	a.revert("expired", "UniswapV2Router: EXPIRED")
+	a.revert("invalidPath", "UniswapV2Library: INVALID_PATH")
+	a.revert("noPair", "UniswapV2Library: PAIR_NOT_FOUND")
+	a.revert("insufficientOutput", "UniswapV2Router: INSUFFICIENT_OUTPUT_AMOUNT")
+	a.bubble()
+
+	return a.bytes()
+}
+
+// multicallCode is Multicall3's aggregate3, making each call with STATICCALL
+func multicallCode() []byte {
+	const (
+		head variable = 0x80 // calldata position of the first call's offset
+		n    variable = 0xa0
+		i    variable = 0xc0
+		call variable = 0xe0 // calldata position of the current call
+		data variable = 0x100
+		size variable = 0x120
+		ptr  variable = 0x140 // end of the encoded results
+		ok   variable = 0x160
+	)
+
+	a := newAssembler()
+	a.dispatch()
+	a.selector("aggregate3((address,bool,bytes)[])", "aggregate3")
+	a.revert("unknown", "")
+
+	a.label("aggregate3")
+	a.arg(0)
+	a.put(4)
+	a.op(vm.ADD, vm.DUP1, vm.CALLDATALOAD)
+	a.store(n)
+	a.put(32)
+	a.op(vm.ADD)
+	a.store(head)
+	a.put(0x20)
+	a.put(output)
+	a.op(vm.MSTORE)
+	a.put(n)
+	a.put(output + 0x20)
+	a.op(vm.MSTORE)
+	a.put(output + 0x40)
+	a.put(n)
+	a.put(5)
+	a.op(vm.SHL, vm.ADD)
+	a.store(ptr)
+	a.put(0)
+	a.store(i)
+
+	a.label("loop")
+	a.put(n)
+	a.put(i)
+	a.op(vm.LT, vm.ISZERO)
+	a.jumpIf("done")
+	a.put(head)
+	a.put(i)
+	a.put(5)
+	a.op(vm.SHL)
+	a.put(head)
+	a.op(vm.ADD, vm.CALLDATALOAD, vm.ADD)
+	a.store(call)
+	a.put(call)
+	a.put(call)
+	a.put(0x40)
+	a.op(vm.ADD, vm.CALLDATALOAD, vm.ADD)
+	a.store(data)
+	a.put(data)
+	a.op(vm.CALLDATALOAD)
+	a.store(size)
+	a.put(size)
+	a.put(data)
+	a.put(32)
+	a.op(vm.ADD)
+	a.put(callIn)
+	a.op(vm.CALLDATACOPY)
+
+	a.put(0)
+	a.put(0)
+	a.put(size)
+	a.put(callIn)
+	a.put(call)
+	a.op(vm.CALLDATALOAD, vm.GAS, vm.STATICCALL)
+	a.store(ok)
+	a.put(ok)
+	a.put(call)
+	a.put(0x20)
+	a.op(vm.ADD, vm.CALLDATALOAD, vm.OR, vm.ISZERO)
+	a.jumpIf("bubble")
+
+	// results[i] = (ok, returndata), its offset relative to the first one
+	a.put(output + 0x40)
+	a.put(ptr)
+	a.op(vm.SUB)
+	a.put(i)
+	a.put(5)
+	a.op(vm.SHL)
+	a.put(output + 0x40)
+	a.op(vm.ADD, vm.MSTORE)
+	a.put(ok)
+	a.put(ptr)
+	a.op(vm.MSTORE)
+	a.put(0x40)
+	a.put(ptr)
+	a.put(0x20)
+	a.op(vm.ADD, vm.MSTORE)
+	a.put(vm.RETURNDATASIZE)
+	a.put(ptr)
+	a.put(0x40)
+	a.op(vm.ADD, vm.MSTORE)
+	a.put(vm.RETURNDATASIZE)
+	a.put(0)
+	a.put(ptr)
+	a.put(0x60)
+	a.op(vm.ADD, vm.RETURNDATACOPY)
+	a.put(vm.RETURNDATASIZE)
+	a.put(31)
+	a.op(vm.ADD)
+	a.put(5)
+	a.op(vm.SHR)
+	a.put(5)
+	a.op(vm.SHL)
+	a.put(0x60)
+	a.op(vm.ADD)
+	a.put(ptr)
+	a.op(vm.ADD)
+	a.store(ptr)
+	a.put(i)
+	a.put(1)
+	a.op(vm.ADD)
+	a.store(i)
+	a.jump("loop")
+
+	a.label("done")
+	a.put(output)
+	a.put(ptr)
+	a.op(vm.SUB)
+	a.put(output)
+	a.op(vm.RETURN)
+	a.bubble()
+
+	return a.bytes()
+}
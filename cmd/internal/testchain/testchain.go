@@ -0,0 +1,276 @@
+// Package testchain runs tests against go-ethereum's simulated backend with
+// mock ERC-20 tokens, Uniswap V2 pairs and router, and Multicall3 set up in
+// the genesis block. The mocks are assembled by hand, so the tests need no
+// Solidity compiler; they follow the real contracts closely enough for the
+// bindings and the router's swap flow.
+package testchain
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+)
+
+// Genesis collects the accounts and contracts of a simulated chain before it starts
+type Genesis struct {
+	alloc   types.GenesisAlloc
+	key     *ecdsa.PrivateKey
+	account common.Address
+	pairs   map[common.Address][2]common.Address
+	next    int64
+}
+
+// NewGenesis returns a genesis with one account holding 100 ETH
+func NewGenesis(t testing.TB) *Genesis {
+	t.Helper()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	account := crypto.PubkeyToAddress(key.PublicKey)
+
+	ether := new(big.Int).Exp(big.NewInt(10), big.NewInt(20), nil)
+	return &Genesis{
+		alloc:   types.GenesisAlloc{account: {Balance: ether}},
+		key:     key,
+		account: account,
+		pairs:   make(map[common.Address][2]common.Address),
+		next:    0x1000,
+	}
+}
+
+// Account returns the funded account the chain's transactions are sent from
+func (g *Genesis) Account() common.Address {
+	return g.account
+}
+
+// Token deploys an ERC-20 at an address derived from its symbol, so the
+// order of two tokens does not depend on the order they were added in
+func (g *Genesis) Token(symbol string, decimals uint8) common.Address {
+	address := common.BytesToAddress(crypto.Keccak256([]byte("testchain " + symbol))[12:])
+	g.alloc[address] = types.Account{Code: tokenCode(symbol, decimals), Storage: make(map[common.Hash]common.Hash)}
+
+	return address
+}
+
+// Mint gives an account tokens
+func (g *Genesis) Mint(token, account common.Address, amount *big.Int) {
+	storage := g.alloc[token].Storage
+	balance := storage[balanceSlot(account)].Big()
+	storage[balanceSlot(account)] = common.BigToHash(balance.Add(balance, amount))
+}
+
+// Pair deploys a pair of two tokens holding the given reserves of each. Like
+// the real factory it sorts the tokens, so tokenA is not necessarily token0.
+func (g *Genesis) Pair(tokenA, tokenB common.Address, reserveA, reserveB *big.Int) common.Address {
+	token0, token1, reserve0, reserve1 := tokenA, tokenB, reserveA, reserveB
+	if token1.Cmp(token0) < 0 {
+		token0, token1, reserve0, reserve1 = token1, token0, reserve1, reserve0
+	}
+
+	address := g.address()
+	g.alloc[address] = types.Account{
+		Code: pairCode(token0, token1),
+		Storage: map[common.Hash]common.Hash{
+			common.BigToHash(big.NewInt(0)): common.BigToHash(reserve0),
+			common.BigToHash(big.NewInt(1)): common.BigToHash(reserve1),
+		},
+	}
+	g.Mint(token0, address, reserve0)
+	g.Mint(token1, address, reserve1)
+	g.pairs[address] = [2]common.Address{token0, token1}
+
+	return address
+}
+
+// Router deploys a router trading through the given pairs
+func (g *Genesis) Router(weth common.Address, pairs ...common.Address) common.Address {
+	storage := make(map[common.Hash]common.Hash)
+	for _, pair := range pairs {
+		tokens := g.pairs[pair]
+		storage[routerSlot(tokens[0], tokens[1])] = common.BytesToHash(pair.Bytes())
+	}
+
+	address := g.address()
+	g.alloc[address] = types.Account{Code: routerCode(weth), Storage: storage}
+
+	return address
+}
+
+// Multicall deploys Multicall3's aggregate3
+func (g *Genesis) Multicall() common.Address {
+	address := g.address()
+	g.alloc[address] = types.Account{Code: multicallCode()}
+
+	return address
+}
+
+// address returns the next unused contract address
+func (g *Genesis) address() common.Address {
+	g.next++
+	return common.BigToAddress(big.NewInt(g.next))
+}
+
+// Chain is a running simulated chain
+type Chain struct {
+	backend *simulated.Backend
+	client  *Client
+	key     *ecdsa.PrivateKey
+	account common.Address
+}
+
+// Start starts the chain, which is closed when the test ends
+func (g *Genesis) Start(t testing.TB) *Chain {
+	t.Helper()
+
+	backend := simulated.NewBackend(g.alloc)
+	t.Cleanup(func() { backend.Close() })
+
+	return &Chain{
+		backend: backend,
+		client:  &Client{ethClient: unwrap(t, backend.Client()), backend: backend},
+		key:     g.key,
+		account: g.account,
+	}
+}
+
+// ethClient names the embedded client so that its Client method, which gives
+// the raw RPC client, is promoted as on *ethclient.Client
+type ethClient = ethclient.Client
+
+// Client is the simulated backend's client, mining a block after every
+// transaction it sends so that waiting for a receipt returns at once
+type Client struct {
+	*ethClient
+	backend *simulated.Backend
+}
+
+// unwrap returns the *ethclient.Client the simulated backend's client embeds.
+// Its field hides the raw RPC client that calls such as eth_simulateV1 need.
+func unwrap(t testing.TB, client simulated.Client) *ethclient.Client {
+	t.Helper()
+
+	value := reflect.ValueOf(client)
+	if value.Kind() == reflect.Struct && value.NumField() > 0 {
+		if inner, ok := value.Field(0).Interface().(*ethclient.Client); ok {
+			return inner
+		}
+	}
+	t.Fatalf("unexpected simulated client %T", client)
+
+	return nil
+}
+
+// SendTransaction sends the transaction and mines it
+func (c *Client) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	if err := c.ethClient.SendTransaction(ctx, tx); err != nil {
+		return err
+	}
+	c.backend.Commit()
+
+	return nil
+}
+
+// Client returns the chain's client
+func (c *Chain) Client() *Client {
+	return c.client
+}
+
+// Account returns the funded account
+func (c *Chain) Account() common.Address {
+	return c.account
+}
+
+// Commit mines a block
+func (c *Chain) Commit() {
+	c.backend.Commit()
+}
+
+// BlockNumber returns the latest block number
+func (c *Chain) BlockNumber(t testing.TB) uint64 {
+	t.Helper()
+
+	number, err := c.client.BlockNumber(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return number
+}
+
+// TransactOpts returns options signing as the funded account
+func (c *Chain) TransactOpts(t testing.TB) *bind.TransactOpts {
+	t.Helper()
+
+	chainID, err := c.client.ChainID(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts, err := bind.NewKeyedTransactorWithChainID(c.key, chainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return opts
+}
+
+// Transact sends a call to a contract from the funded account and fails the
+// test unless it is mined successfully
+func (c *Chain) Transact(t testing.TB, to common.Address, data []byte) {
+	t.Helper()
+
+	contract := bind.NewBoundContract(to, abi.ABI{}, c.client, c.client, c.client)
+	tx, err := contract.RawTransact(c.TransactOpts(t), data)
+	if err != nil {
+		t.Fatalf("sending to %s: %v", to.Hex(), err)
+	}
+	receipt, err := bind.WaitMined(context.Background(), c.client, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		t.Fatalf("transaction to %s reverted", to.Hex())
+	}
+}
+
+// Transfer sends tokens from the funded account
+func (c *Chain) Transfer(t testing.TB, token, to common.Address, amount *big.Int) {
+	t.Helper()
+
+	c.Transact(t, token, calldata("transfer(address,uint256)", common.BytesToHash(to.Bytes()), common.BigToHash(amount)))
+}
+
+// Approve lets spender take tokens from the funded account
+func (c *Chain) Approve(t testing.TB, token, spender common.Address, amount *big.Int) {
+	t.Helper()
+
+	c.Transact(t, token, calldata("approve(address,uint256)", common.BytesToHash(spender.Bytes()), common.BigToHash(amount)))
+}
+
+// Sync makes a pair take its token balances as its reserves
+func (c *Chain) Sync(t testing.TB, pair common.Address) {
+	t.Helper()
+
+	c.Transact(t, pair, calldata("sync()"))
+}
+
+// calldata packs a call with static arguments
+func calldata(signature string, args ...common.Hash) []byte {
+	data := crypto.Keccak256([]byte(signature))[:4]
+	for _, arg := range args {
+		data = append(data, arg.Bytes()...)
+	}
+
+	return data
+}
@@ -0,0 +1,204 @@
+package tokens
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/contracts/erc20"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// MaxAmount is the --amount value selecting the whole balance
+const MaxAmount = "max"
+
+// ErrNeedsBalance is returned by Amount.BaseUnits for "max" and percentages when no balance is given
+var ErrNeedsBalance = errors.New("amount is relative to the balance, which is unknown")
+
+// Amount is an --amount value: a number of whole tokens such as "1.5" or
+// "2e-3", "max" for the whole balance, or a percentage of it such as "25%"
+type Amount struct {
+	text    string
+	whole   *big.Rat // whole tokens, nil for a share of the balance
+	percent *big.Rat // percentage of the balance, nil for whole tokens
+}
+
+// ParseAmount parses an --amount value. Amounts must be positive and
+// percentages at most 100%.
+func ParseAmount(text string) (Amount, error) {
+	text = strings.TrimSpace(text)
+	amount := Amount{text: text}
+
+	if strings.EqualFold(text, MaxAmount) {
+		amount.percent = big.NewRat(100, 1)
+		return amount, nil
+	}
+
+	if number, ok := strings.CutSuffix(text, "%"); ok {
+		percent, err := parsePositive(number)
+		if err != nil {
+			return Amount{}, fmt.Errorf("%q is not a valid percentage: %w", text, err)
+		}
+		if percent.Cmp(big.NewRat(100, 1)) > 0 {
+			return Amount{}, fmt.Errorf("%q is more than the whole balance", text)
+		}
+		amount.percent = percent
+		return amount, nil
+	}
+
+	whole, err := parsePositive(text)
+	if err != nil {
+		return Amount{}, fmt.Errorf("%q is not a valid amount: %w", text, err)
+	}
+	amount.whole = whole
+
+	return amount, nil
+}
+
+// parsePositive parses a positive decimal number, optionally in scientific notation
+func parsePositive(number string) (*big.Rat, error) {
+	if number == "" || strings.Trim(number, "0123456789.eE+-") != "" {
+		return nil, errors.New("use a decimal number such as 1.5 or 2e-3")
+	}
+
+	value, ok := new(big.Rat).SetString(number)
+	if !ok {
+		return nil, errors.New("use a decimal number such as 1.5 or 2e-3")
+	}
+	if value.Sign() <= 0 {
+		return nil, errors.New("must be positive")
+	}
+
+	return value, nil
+}
+
+// RelativeToBalance reports whether the amount is "max" or a percentage,
+// which BaseUnits can only convert given the balance
+func (a Amount) RelativeToBalance() bool {
+	return a.percent != nil
+}
+
+// BaseUnits converts the amount into base units of a token with the given
+// decimals. Whole-token amounts with more decimal places than the token has
+// are refused; a share of the balance is rounded down. balance is only used
+// for amounts relative to it and may be nil otherwise.
+func (a Amount) BaseUnits(decimals uint8, balance *big.Int) (*big.Int, error) {
+	if a.whole == nil && a.percent == nil {
+		return nil, errors.New("amount is not set")
+	}
+
+	if a.percent != nil {
+		if balance == nil {
+			return nil, ErrNeedsBalance
+		}
+		share := new(big.Int).Mul(balance, a.percent.Num())
+		share.Quo(share, new(big.Int).Mul(a.percent.Denom(), big.NewInt(100)))
+		if share.Sign() == 0 {
+			return nil, fmt.Errorf("%s of the balance is zero", a.text)
+		}
+		return share, nil
+	}
+
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	units := new(big.Rat).Mul(a.whole, new(big.Rat).SetInt(scale))
+	if !units.IsInt() {
+		return nil, fmt.Errorf("%s has more than %d decimal places", a.text, decimals)
+	}
+
+	return new(big.Int).Set(units.Num()), nil
+}
+
+// String returns the amount as it was given
+func (a Amount) String() string {
+	return a.text
+}
+
+// ParseUnits converts a decimal number of whole tokens, such as "1.5" or
+// "2e-3", into base units of a token with the given decimals
+func ParseUnits(text string, decimals uint8) (*big.Int, error) {
+	amount, err := ParseAmount(text)
+	if err != nil {
+		return nil, err
+	}
+	if amount.RelativeToBalance() {
+		return nil, fmt.Errorf("%q is relative to a balance, give a number", text)
+	}
+
+	return amount.BaseUnits(decimals, nil)
+}
+
+// BalanceReader reads ether balances and calls token contracts. *ethclient.Client satisfies it.
+type BalanceReader interface {
+	bind.ContractCaller
+	BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error)
+}
+
+// Balance returns the owner's balance of the token in base units
+func Balance(ctx context.Context, client BalanceReader, token Token, owner common.Address) (*big.Int, error) {
+	if token.IsNative() {
+		balance, err := client.BalanceAt(ctx, owner, nil)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s balance of %s: %w", token.Symbol, owner.Hex(), err)
+		}
+		return balance, nil
+	}
+
+	contract, err := erc20.NewIERC20Caller(token.Address, client)
+	if err != nil {
+		return nil, fmt.Errorf("binding token %s: %w", token.Symbol, err)
+	}
+	balance, err := contract.BalanceOf(&bind.CallOpts{Context: ctx}, owner)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s balance of %s: %w", token.Symbol, owner.Hex(), err)
+	}
+
+	return balance, nil
+}
+
+// Spendable returns the balance "max" and percentages are taken from: the
+// owner's balance of the token, less the most the transaction sent with opts
+// can cost in gas when the token is the native currency that also pays for it
+func Spendable(ctx context.Context, client BalanceReader, token Token, opts *bind.TransactOpts) (*big.Int, error) {
+	balance, err := Balance(ctx, client, token, opts.From)
+	if err != nil || !token.IsNative() {
+		return balance, err
+	}
+
+	fee, err := maxGasFee(ctx, client, opts)
+	if err != nil {
+		return nil, err
+	}
+	if balance.Cmp(fee) <= 0 {
+		return nil, fmt.Errorf("the %s balance of %s does not cover the gas of the swap", Native.Format(balance), opts.From.Hex())
+	}
+
+	return balance.Sub(balance, fee), nil
+}
+
+// maxGasFee returns gas limit × gas price for opts. Without a gas price the
+// node's suggestion is doubled, as EIP-1559 fee caps allow twice the base fee.
+func maxGasFee(ctx context.Context, client BalanceReader, opts *bind.TransactOpts) (*big.Int, error) {
+	if opts.GasLimit == 0 {
+		return nil, errors.New("a gas limit is needed to keep the gas out of a native balance")
+	}
+
+	price := opts.GasPrice
+	if price == nil {
+		suggester, ok := client.(interface {
+			SuggestGasPrice(ctx context.Context) (*big.Int, error)
+		})
+		if !ok {
+			return nil, errors.New("no gas price is set and the client cannot suggest one")
+		}
+		suggested, err := suggester.SuggestGasPrice(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("suggesting gas price: %w", err)
+		}
+		price = new(big.Int).Mul(suggested, big.NewInt(2))
+	}
+
+	return new(big.Int).Mul(price, new(big.Int).SetUint64(opts.GasLimit)), nil
+}
@@ -0,0 +1,108 @@
+package tokens
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		text     string
+		decimals uint8
+		balance  *big.Int
+		want     string // base units, empty when BaseUnits fails
+	}{
+		{"1.5", 6, nil, "1500000"},
+		{" 2 ", 18, nil, "2000000000000000000"},
+		{"2e-3", 6, nil, "2000"},
+		{"1.5E3", 0, nil, "1500"},
+		{"1e+2", 2, nil, "10000"},
+		{"0.000001", 6, nil, "1"},
+		{"1e-6", 6, nil, "1"},
+		{"0.0000015", 6, nil, ""}, // more decimal places than USDC has
+		{"1e-7", 6, nil, ""},
+		{"0.1", 0, nil, ""},
+		{"max", 6, big.NewInt(123456789), "123456789"},
+		{"MAX", 6, big.NewInt(1), "1"},
+		{"100%", 18, big.NewInt(1000), "1000"},
+		{"25%", 18, big.NewInt(1000), "250"},
+		{"2.5e1%", 18, big.NewInt(1000), "250"},
+		{"33.3333%", 18, big.NewInt(100), "33"}, // rounded down
+		{"50%", 18, big.NewInt(3), "1"},         // 1.5 rounded down
+		{"0.001%", 18, big.NewInt(999), ""},     // rounds down to nothing
+		{"1e-18", 18, big.NewInt(0), "1"},       // whole amounts ignore the balance
+		{"12345678901234567890.123456789012345678", 18, nil, "12345678901234567890123456789012345678"},
+	}
+	for _, test := range tests {
+		amount, err := ParseAmount(test.text)
+		if err != nil {
+			t.Errorf("ParseAmount(%q): %v", test.text, err)
+			continue
+		}
+		got, err := amount.BaseUnits(test.decimals, test.balance)
+		switch {
+		case test.want == "" && err == nil:
+			t.Errorf("%q with %d decimals = %s, want an error", test.text, test.decimals, got)
+		case test.want != "" && err != nil:
+			t.Errorf("%q with %d decimals: %v", test.text, test.decimals, err)
+		case test.want != "" && got.String() != test.want:
+			t.Errorf("%q with %d decimals = %s, want %s", test.text, test.decimals, got, test.want)
+		}
+	}
+}
+
+func TestParseAmountRejects(t *testing.T) {
+	for _, text := range []string{"", "0", "-1", "0%", "-5%", "100.1%", "101%", "1,5", "0x10", "1/2", "abc", "1e", "%", "ma x", "inf", "NaN"} {
+		if amount, err := ParseAmount(text); err == nil {
+			t.Errorf("ParseAmount(%q) = %v, want an error", text, amount)
+		}
+	}
+}
+
+func TestBaseUnitsNeedsBalance(t *testing.T) {
+	for _, text := range []string{"max", "50%"} {
+		amount, err := ParseAmount(text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !amount.RelativeToBalance() {
+			t.Errorf("%q is not relative to the balance", text)
+		}
+		if _, err := amount.BaseUnits(18, nil); !errors.Is(err, ErrNeedsBalance) {
+			t.Errorf("%q without a balance: error = %v, want ErrNeedsBalance", text, err)
+		}
+	}
+
+	if _, err := ParseUnits("max", 18); err == nil {
+		t.Error("ParseUnits accepted max")
+	}
+	if units, err := ParseUnits("2.5", 2); err != nil || units.Int64() != 250 {
+		t.Errorf("ParseUnits(2.5, 2) = %v, %v", units, err)
+	}
+	if (Amount{}).RelativeToBalance() {
+		t.Error("the zero amount is relative to the balance")
+	}
+	if _, err := (Amount{}).BaseUnits(18, big.NewInt(1)); err == nil {
+		t.Error("the zero amount converted")
+	}
+}
+
+func TestFormatUnits(t *testing.T) {
+	tests := []struct {
+		amount   int64
+		decimals uint8
+		want     string
+	}{
+		{1500000, 6, "1.5"},
+		{1, 6, "0.000001"},
+		{2000000, 6, "2"},
+		{0, 18, "0"},
+		{1500, 0, "1500"},
+	}
+	for _, test := range tests {
+		if got := FormatUnits(big.NewInt(test.amount), test.decimals); got != test.want {
+			t.Errorf("FormatUnits(%d, %d) = %s, want %s", test.amount, test.decimals, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package tokens
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/registry"
+	"gopkg.in/yaml.v3"
+)
+
+// File is the content of a token registry file, in YAML or, with a .json extension, JSON:
+//
+//	include_builtin: true   # merge with the built-in tokens (default); false to replace them
+//	tokens:
+//	  - symbol: USDC
+//	    address: "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"
+//	    decimals: 6         # optional, read on-chain when left out
+//
+// Addresses must be EIP-55 checksummed so a mistyped character is caught.
+type File struct {
+	IncludeBuiltin *bool                `yaml:"include_builtin,omitempty" json:"include_builtin,omitempty"`
+	Tokens         []registry.FileToken `yaml:"tokens" json:"tokens"`
+}
+
+// Load returns the token registry: the built-in tokens merged with the token
+// registry file at path, then the tokens of pools that the pool registry gives
+// an address. An empty path leaves out the file.
+func Load(path string, pools *registry.Registry) (*Registry, error) {
+	r := Builtin()
+
+	if path != "" {
+		file, err := ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if file.IncludeBuiltin != nil && !*file.IncludeBuiltin {
+			r = New()
+		}
+		if err := file.mergeInto(r, path); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	if pools != nil {
+		r.AddPools(pools)
+	}
+
+	return r, nil
+}
+
+// ReadFile parses a token registry file. Unknown keys are rejected so a
+// misspelled field is not silently ignored.
+func ReadFile(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading token registry: %w", err)
+	}
+
+	file := &File{}
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(file)
+	} else {
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		err = decoder.Decode(file)
+	}
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parsing token registry %s: %w", path, err)
+	}
+
+	return file, nil
+}
+
+// mergeInto validates every token of the file and adds it to r, replacing
+// registered tokens of the same symbol
+func (f *File) mergeInto(r *Registry, source string) error {
+	symbols := make(map[string]bool, len(f.Tokens))
+
+	for i, fileToken := range f.Tokens {
+		name := fmt.Sprintf("token #%d", i+1)
+		if fileToken.Symbol != "" {
+			name = "token " + fileToken.Symbol
+		}
+
+		if fileToken.Address == "" {
+			return fmt.Errorf("%s: address is required", name)
+		}
+		token, err := fileToken.Token()
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+
+		if symbols[symbolKey(token.Symbol)] {
+			return fmt.Errorf("token %s is listed twice", token.Symbol)
+		}
+		symbols[symbolKey(token.Symbol)] = true
+
+		err = r.Add(Token{Symbol: token.Symbol, Address: token.Address, Decimals: token.Decimals, Source: source})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,202 @@
+// Package tokens turns the token symbols and addresses given on the command
+// line into complete tokens. It knows the built-in tokens from the constants
+// package, the tokens declared in the pool registry and an optional token
+// registry file, and reads anything else from the token contract. It also
+// formats base-unit amounts and scales reserve ratios between tokens of
+// different decimals.
+package tokens
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/constants"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/registry"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// BuiltinSource is the Source of tokens compiled into the binary
+const BuiltinSource = "built-in"
+
+// Token is an ERC-20 token, or the native currency when its address is zero
+type Token struct {
+	Symbol   string
+	Address  common.Address
+	Decimals uint8  // 0 when unknown and read on-chain
+	Source   string // BuiltinSource, the file the token came from, or "on-chain"
+}
+
+// IsNative reports whether the token is the chain's native currency
+func (t Token) IsNative() bool {
+	return t.Address == (common.Address{})
+}
+
+// Native is the chain's native currency
+var Native = Token{Symbol: constants.NativeSymbol, Decimals: constants.NativeDecimals, Source: BuiltinSource}
+
+// Registry is a set of tokens indexed by symbol and address. Symbols are
+// matched case-insensitively, so a registry cannot hold two tokens whose
+// symbols only differ in case.
+type Registry struct {
+	symbols   map[string]*Token
+	addresses map[common.Address]*Token
+
+	// Symbols declared by pool tokens at several addresses, which must be given by address
+	ambiguous map[string][]common.Address
+}
+
+// New returns a registry holding only the native currency
+func New() *Registry {
+	r := &Registry{
+		symbols:   make(map[string]*Token),
+		addresses: make(map[common.Address]*Token),
+		ambiguous: make(map[string][]common.Address),
+	}
+	native := Native
+	r.symbols[symbolKey(native.Symbol)] = &native
+
+	return r
+}
+
+// Builtin returns the registry of the tokens in constants.TokenAddresses
+func Builtin() *Registry {
+	r := New()
+
+	for symbol, address := range constants.TokenAddresses {
+		r.put(&Token{
+			Symbol:   symbol,
+			Address:  common.HexToAddress(address),
+			Decimals: constants.TokenDecimals[symbol],
+			Source:   BuiltinSource,
+		})
+	}
+
+	return r
+}
+
+// Add registers a token. A token with the same symbol replaces the existing
+// one; a token reusing the address of a token with another symbol is refused.
+func (r *Registry) Add(token Token) error {
+	if token.IsNative() {
+		return fmt.Errorf("token %s has no address", token.Symbol)
+	}
+	if strings.EqualFold(token.Symbol, constants.NativeSymbol) {
+		return fmt.Errorf("%s is the native currency and cannot be registered as a token", token.Symbol)
+	}
+	if existing, ok := r.addresses[token.Address]; ok && symbolKey(existing.Symbol) != symbolKey(token.Symbol) {
+		return fmt.Errorf("token %s has the address of %s token %s (%s)", token.Symbol, existing.Source, existing.Symbol, token.Address.Hex())
+	}
+
+	if existing, ok := r.symbols[symbolKey(token.Symbol)]; ok {
+		delete(r.addresses, existing.Address)
+	}
+	delete(r.ambiguous, symbolKey(token.Symbol))
+	r.put(&token)
+
+	return nil
+}
+
+// AddPools registers the pool tokens whose address the pool registry knows,
+// without replacing registered tokens. A symbol found at several addresses is
+// not registered and has to be given by address.
+func (r *Registry) AddPools(pools *registry.Registry) {
+	found := make(map[string][]Token)
+	var symbols []string
+
+	for _, pool := range pools.Pools() {
+		for _, poolToken := range []registry.Token{pool.Token0, pool.Token1} {
+			key := symbolKey(poolToken.Symbol)
+			if poolToken.Address == (common.Address{}) || r.symbols[key] != nil {
+				continue
+			}
+			if _, taken := r.addresses[poolToken.Address]; taken {
+				continue
+			}
+
+			if _, ok := found[key]; !ok {
+				symbols = append(symbols, key)
+			}
+			duplicate := false
+			for _, token := range found[key] {
+				duplicate = duplicate || token.Address == poolToken.Address
+			}
+			if !duplicate {
+				found[key] = append(found[key], Token{
+					Symbol:   poolToken.Symbol,
+					Address:  poolToken.Address,
+					Decimals: poolToken.Decimals,
+					Source:   pool.Source,
+				})
+			}
+		}
+	}
+
+	for _, key := range symbols {
+		candidates := found[key]
+		if len(candidates) == 1 {
+			r.put(&candidates[0])
+			continue
+		}
+		for _, token := range candidates {
+			r.ambiguous[key] = append(r.ambiguous[key], token.Address)
+		}
+	}
+}
+
+// put indexes a token without any checks
+func (r *Registry) put(token *Token) {
+	r.symbols[symbolKey(token.Symbol)] = token
+	r.addresses[token.Address] = token
+}
+
+// Symbol returns the token with the given symbol
+func (r *Registry) Symbol(symbol string) (Token, bool) {
+	token, ok := r.symbols[symbolKey(symbol)]
+	if !ok {
+		return Token{}, false
+	}
+
+	return *token, true
+}
+
+// ByAddress returns the token at the given address
+func (r *Registry) ByAddress(address common.Address) (Token, bool) {
+	token, ok := r.addresses[address]
+	if !ok {
+		return Token{}, false
+	}
+
+	return *token, true
+}
+
+// Ambiguous returns the addresses of a symbol declared by several pool tokens
+func (r *Registry) Ambiguous(symbol string) []common.Address {
+	return r.ambiguous[symbolKey(symbol)]
+}
+
+// Tokens returns all tokens ordered by symbol, the native currency first
+func (r *Registry) Tokens() []Token {
+	tokens := make([]Token, 0, len(r.symbols))
+	for _, token := range r.symbols {
+		tokens = append(tokens, *token)
+	}
+	sort.Slice(tokens, func(i, j int) bool {
+		if tokens[i].IsNative() != tokens[j].IsNative() {
+			return tokens[i].IsNative()
+		}
+		return symbolKey(tokens[i].Symbol) < symbolKey(tokens[j].Symbol)
+	})
+
+	return tokens
+}
+
+// Len returns the number of tokens, including the native currency
+func (r *Registry) Len() int {
+	return len(r.symbols)
+}
+
+// symbolKey is the case-insensitive index of a symbol
+func symbolKey(symbol string) string {
+	return strings.ToUpper(symbol)
+}
@@ -0,0 +1,132 @@
+package tokens
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/constants"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/registry"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Checksummed token addresses for the test registries
+var (
+	addressA = common.HexToAddress("0xabcdefabcdefabcdefabcdefabcdefabcdefabcd")
+	addressB = common.HexToAddress("0x00000000000000000000000000000000000a11ce")
+)
+
+func TestRegistryAdd(t *testing.T) {
+	r := Builtin()
+	if r.Len() != len(constants.TokenAddresses)+1 {
+		t.Errorf("builtin registry has %d tokens, want %d and the native currency", r.Len(), len(constants.TokenAddresses))
+	}
+	if usdc, ok := r.Symbol("usdc"); !ok || usdc.Decimals != 6 || usdc.Source != BuiltinSource {
+		t.Errorf("USDC = %+v", usdc)
+	}
+	if native, ok := r.Symbol(constants.NativeSymbol); !ok || !native.IsNative() {
+		t.Errorf("%s = %+v", constants.NativeSymbol, native)
+	}
+
+	// A symbol differing only in case replaces the token and frees its address
+	usdc := common.HexToAddress(constants.TokenAddresses["USDC"])
+	if err := r.Add(Token{Symbol: "UsDc", Address: addressA, Decimals: 6}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := r.ByAddress(usdc); ok {
+		t.Error("the replaced USDC address is still registered")
+	}
+	if token, _ := r.Symbol("USDC"); token.Address != addressA {
+		t.Errorf("USDC is at %s, want %s", token.Address.Hex(), addressA.Hex())
+	}
+
+	tests := []struct {
+		name  string
+		token Token
+	}{
+		{"address of another symbol", Token{Symbol: "DAI2", Address: addressA}},
+		{"no address", Token{Symbol: "X"}},
+		{"native symbol", Token{Symbol: "eth", Address: addressB}},
+	}
+	for _, test := range tests {
+		if err := r.Add(test.token); err == nil {
+			t.Errorf("%s: %+v was added", test.name, test.token)
+		}
+	}
+}
+
+func TestRegistryAddPools(t *testing.T) {
+	pools := registry.New()
+	add := func(name string, token0, token1 registry.Token) {
+		if err := pools.Add(&registry.Pool{Name: name, Address: common.HexToAddress(name), Token0: token0, Token1: token1}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	add("0x01", registry.Token{Symbol: "eUSD", Address: addressA, Decimals: 6}, registry.Token{Symbol: "eEUR"})
+	add("0x02", registry.Token{Symbol: "eusd", Address: addressB, Decimals: 18}, registry.Token{Symbol: "eGBP", Address: common.HexToAddress("0x0c")})
+	add("0x03", registry.Token{Symbol: "eGBP", Address: common.HexToAddress("0x0c")}, registry.Token{Symbol: "USDC", Address: common.HexToAddress("0x0d")})
+
+	r := Builtin()
+	r.AddPools(pools)
+
+	// eUSD is at two addresses, so it can only be given by address
+	if _, ok := r.Symbol("eUSD"); ok {
+		t.Error("an ambiguous symbol was registered")
+	}
+	if got := r.Ambiguous("EUSD"); len(got) != 2 || got[0] != addressA || got[1] != addressB {
+		t.Errorf("Ambiguous(eUSD) = %v", got)
+	}
+
+	// Tokens without an address are left to the chain, the same token in two pools is one token
+	if _, ok := r.Symbol("eEUR"); ok {
+		t.Error("a pool token without an address was registered")
+	}
+	if gbp, ok := r.Symbol("eGBP"); !ok || gbp.Address != common.HexToAddress("0x0c") {
+		t.Errorf("eGBP = %+v", gbp)
+	}
+
+	// Registered tokens are not replaced by pool tokens
+	if usdc, _ := r.Symbol("USDC"); usdc.Address.Hex() != constants.TokenAddresses["USDC"] {
+		t.Errorf("USDC was replaced by %s", usdc.Address.Hex())
+	}
+}
+
+func TestLoad(t *testing.T) {
+	write := func(content string) string {
+		path := filepath.Join(t.TempDir(), "tokens.yaml")
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	r, err := Load(write("include_builtin: false\ntokens:\n  - {symbol: eUSD, address: \""+addressA.Hex()+"\", decimals: 6}\n"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Len() != 2 {
+		t.Errorf("registry has %d tokens, want eUSD and the native currency", r.Len())
+	}
+	if usd, ok := r.Symbol("eUSD"); !ok || usd.Decimals != 6 || !strings.HasSuffix(usd.Source, "tokens.yaml") {
+		t.Errorf("eUSD = %+v", usd)
+	}
+
+	tests := []struct {
+		name   string
+		tokens string
+		want   string
+	}{
+		{"duplicate symbol", "  - {symbol: eUSD, address: \"" + addressA.Hex() + "\"}\n  - {symbol: EUSD, address: \"" + addressB.Hex() + "\"}\n", "listed twice"},
+		{"duplicate address", "  - {symbol: eUSD, address: \"" + addressA.Hex() + "\"}\n  - {symbol: eEUR, address: \"" + addressA.Hex() + "\"}\n", "has the address of"},
+		{"bad checksum", "  - {symbol: eUSD, address: \"" + strings.ToLower(addressA.Hex()) + "\"}\n", "not checksummed"},
+		{"missing address", "  - {symbol: eUSD}\n", "address is required"},
+		{"misspelled field", "  - {symbol: eUSD, adress: \"" + addressA.Hex() + "\"}\n", "adress"},
+	}
+	for _, test := range tests {
+		_, err := Load(write("tokens:\n"+test.tokens), nil)
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: error = %v, want one mentioning %q", test.name, err, test.want)
+		}
+	}
+}
@@ -0,0 +1,227 @@
+package tokens
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/contracts/erc20"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/poolreader"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/registry"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// OnChainSource is the Source of tokens read from their contract
+const OnChainSource = "on-chain"
+
+// Options configures a Resolver
+type Options struct {
+	Tokens *Registry             // registered tokens
+	Pools  *registry.Registry    // pools whose tokens can be resolved by symbol, may be nil
+	Reader poolreader.PoolReader // reads pool tokens, may be nil
+	Caller bind.ContractCaller   // reads token contracts, defaults to a live reader's client
+}
+
+// Resolver turns symbols and addresses into complete tokens, reading what the
+// registries do not know from the chain. On-chain reads are cached for the
+// resolver's lifetime.
+type Resolver struct {
+	tokens *Registry
+	pools  *registry.Registry
+	reader poolreader.PoolReader
+	caller bind.ContractCaller
+
+	mu    sync.Mutex
+	cache map[common.Address]Token
+}
+
+// NewResolver creates a resolver. Without a caller, or a live reader to take
+// one from, tokens must be fully known to the registries or the pool reader.
+func NewResolver(opts Options) *Resolver {
+	// Live readers, pinned or not, share their client
+	caller := opts.Caller
+	if live, ok := opts.Reader.(interface{ Backend() poolreader.Backend }); ok && caller == nil {
+		caller = live.Backend()
+	}
+
+	return &Resolver{
+		tokens: opts.Tokens,
+		pools:  opts.Pools,
+		reader: opts.Reader,
+		caller: caller,
+		cache:  make(map[common.Address]Token),
+	}
+}
+
+// Resolve returns the token with the given symbol or address
+func (r *Resolver) Resolve(ctx context.Context, symbolOrAddress string) (Token, error) {
+	if common.IsHexAddress(symbolOrAddress) {
+		return r.Token(ctx, common.HexToAddress(symbolOrAddress))
+	}
+
+	if token, ok := r.tokens.Symbol(symbolOrAddress); ok {
+		if token.IsNative() || token.Decimals != 0 {
+			return token, nil
+		}
+		return r.Token(ctx, token.Address)
+	}
+
+	if addresses := r.tokens.Ambiguous(symbolOrAddress); len(addresses) > 0 {
+		return Token{}, ambiguous(symbolOrAddress, addresses)
+	}
+
+	token, err := r.poolToken(ctx, symbolOrAddress)
+	if err != nil {
+		return Token{}, err
+	}
+	if token == nil {
+		return Token{}, fmt.Errorf("unknown token %s: add it to the token registry file or give its address", symbolOrAddress)
+	}
+
+	return *token, nil
+}
+
+// Token returns the token at address, reading its symbol and decimals from
+// the contract when the registry does not know them
+func (r *Resolver) Token(ctx context.Context, address common.Address) (Token, error) {
+	if address == (common.Address{}) {
+		return Native, nil
+	}
+
+	registered, known := r.tokens.ByAddress(address)
+	if known && registered.Decimals != 0 {
+		return registered, nil
+	}
+
+	r.mu.Lock()
+	cached, ok := r.cache[address]
+	r.mu.Unlock()
+	if ok {
+		return cached, nil
+	}
+
+	if r.caller == nil {
+		if known {
+			return Token{}, fmt.Errorf("decimals of %s are unknown and there is no chain to read them from", registered.Symbol)
+		}
+		return Token{}, fmt.Errorf("unknown token %s and there is no chain to read it from", address.Hex())
+	}
+
+	contract, err := erc20.NewIERC20Caller(address, r.caller)
+	if err != nil {
+		return Token{}, fmt.Errorf("binding token %s: %w", address.Hex(), err)
+	}
+	opts := &bind.CallOpts{Context: ctx}
+
+	token := Token{Address: address, Source: OnChainSource}
+	if known {
+		token.Symbol, token.Source = registered.Symbol, registered.Source
+	} else if token.Symbol, err = contract.Symbol(opts); err != nil {
+		return Token{}, fmt.Errorf("calling symbol on %s: %w", address.Hex(), err)
+	}
+	if token.Decimals, err = contract.Decimals(opts); err != nil {
+		return Token{}, fmt.Errorf("calling decimals on %s: %w", address.Hex(), err)
+	}
+
+	r.mu.Lock()
+	r.cache[address] = token
+	r.mu.Unlock()
+
+	return token, nil
+}
+
+// PoolTokens returns a pool's token0 and token1, from the pool registry when it
+// knows their addresses and decimals and from the pool reader otherwise.
+// Registered tokens are put in address order, which is how a Uniswap V2 pair
+// sorts its token0 and token1.
+func (r *Resolver) PoolTokens(ctx context.Context, pool *registry.Pool) (Token, Token, error) {
+	if complete(pool.Token0) && complete(pool.Token1) {
+		token0, token1 := fromPool(pool.Token0, pool.Source), fromPool(pool.Token1, pool.Source)
+		if bytes.Compare(token0.Address[:], token1.Address[:]) > 0 {
+			token0, token1 = token1, token0
+		}
+		return token0, token1, nil
+	}
+	if r.reader == nil {
+		return Token{}, Token{}, fmt.Errorf("tokens of %s are unknown and there is no pool reader", pool.Name)
+	}
+
+	token0, token1, err := r.reader.Tokens(ctx, pool.Address)
+	if err != nil {
+		return Token{}, Token{}, fmt.Errorf("reading tokens of %s: %w", pool.Name, err)
+	}
+
+	return fromReader(token0, pool.Source), fromReader(token1, pool.Source), nil
+}
+
+// poolToken finds a token by symbol among the pool tokens. It returns nil when
+// no pool has it and an error when pools trade different tokens of that symbol.
+func (r *Resolver) poolToken(ctx context.Context, symbol string) (*Token, error) {
+	if r.pools == nil {
+		return nil, nil
+	}
+
+	var found *Token
+	var addresses []common.Address
+	seen := make(map[common.Address]bool)
+	for _, pool := range r.pools.Pools() {
+		if !strings.EqualFold(pool.Token0.Symbol, symbol) && !strings.EqualFold(pool.Token1.Symbol, symbol) {
+			continue
+		}
+
+		token0, token1, err := r.PoolTokens(ctx, pool)
+		if err != nil {
+			return nil, err
+		}
+		for _, token := range []Token{token0, token1} {
+			if !strings.EqualFold(token.Symbol, symbol) || seen[token.Address] {
+				continue
+			}
+			seen[token.Address] = true
+			addresses = append(addresses, token.Address)
+			if found == nil {
+				match := token
+				found = &match
+			}
+		}
+	}
+
+	if len(addresses) > 1 {
+		return nil, ambiguous(symbol, addresses)
+	}
+
+	return found, nil
+}
+
+// complete reports whether a pool token's address and decimals are both known
+func complete(token registry.Token) bool {
+	return token.Address != (common.Address{}) && token.Decimals != 0
+}
+
+// Registry converts the token to a pool registry token
+func (t Token) Registry() registry.Token {
+	return registry.Token{Symbol: t.Symbol, Address: t.Address, Decimals: t.Decimals}
+}
+
+// fromPool converts a complete pool registry token
+func fromPool(token registry.Token, source string) Token {
+	return Token{Symbol: token.Symbol, Address: token.Address, Decimals: token.Decimals, Source: source}
+}
+
+// fromReader converts a token read through a pool reader
+func fromReader(token poolreader.Token, source string) Token {
+	return Token{Symbol: token.Symbol, Address: token.Address, Decimals: token.Decimals, Source: source}
+}
+
+// ambiguous is the error for a symbol shared by tokens at several addresses
+func ambiguous(symbol string, addresses []common.Address) error {
+	hexes := make([]string, len(addresses))
+	for i, address := range addresses {
+		hexes[i] = address.Hex()
+	}
+
+	return fmt.Errorf("token symbol %s is used by %s: give the address instead", symbol, strings.Join(hexes, ", "))
+}
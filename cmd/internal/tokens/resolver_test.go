@@ -0,0 +1,175 @@
+package tokens
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/poolreader"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/registry"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/testchain"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// resolverChain is a simulated chain with two tokens called eUSD (in different
+// cases, as testchain derives token addresses from symbols), an eGBP and an
+// eJPY, and registered pools that only name their tokens by symbol
+type resolverChain struct {
+	*testchain.Chain
+	usd6, usd18, gbp, jpy common.Address
+	pools                 *registry.Registry
+}
+
+func newResolverChain(t *testing.T) *resolverChain {
+	genesis := testchain.NewGenesis(t)
+	c := &resolverChain{
+		usd6:  genesis.Token("eUSD", 6),
+		usd18: genesis.Token("EUSD", 18),
+		gbp:   genesis.Token("eGBP", 18),
+		jpy:   genesis.Token("eJPY", 8),
+		pools: registry.New(),
+	}
+
+	pools := []struct {
+		name           string
+		tokenA, tokenB common.Address
+		symbolB        string
+	}{
+		{"eUSD_eGBP_Pool", c.usd6, c.gbp, "eGBP"},
+		{"eUSD_eJPY_Pool", c.usd18, c.jpy, "eJPY"},
+	}
+	for _, pool := range pools {
+		address := genesis.Pair(pool.tokenA, pool.tokenB, big.NewInt(1e12), big.NewInt(1e12))
+		registered := &registry.Pool{Name: pool.name, Address: address, Token0: registry.Token{Symbol: "eUSD"}, Token1: registry.Token{Symbol: pool.symbolB}}
+		if err := c.pools.Add(registered); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c.Chain = genesis.Start(t)
+
+	return c
+}
+
+// resolver returns a resolver reading the chain through a live pool reader
+func (c *resolverChain) resolver(tokens *Registry) *Resolver {
+	return NewResolver(Options{Tokens: tokens, Pools: c.pools, Reader: poolreader.NewLive(c.Client())})
+}
+
+func TestResolveRegisteredTokens(t *testing.T) {
+	ctx := context.Background()
+	r := NewResolver(Options{Tokens: Builtin()})
+
+	usdc, err := r.Resolve(ctx, "usdc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if usdc.Symbol != "USDC" || usdc.Decimals != 6 {
+		t.Errorf("usdc = %+v", usdc)
+	}
+	if byAddress, err := r.Resolve(ctx, usdc.Address.Hex()); err != nil || byAddress != usdc {
+		t.Errorf("USDC by address = %+v, %v", byAddress, err)
+	}
+	if native, err := r.Resolve(ctx, "ETH"); err != nil || !native.IsNative() {
+		t.Errorf("ETH = %+v, %v", native, err)
+	}
+
+	// Without a chain, anything the registries do not fully know is an error
+	if _, err := r.Resolve(ctx, addressA.Hex()); err == nil {
+		t.Error("an unknown address resolved without a chain")
+	}
+	if _, err := r.Resolve(ctx, "eNOPE"); err == nil || !strings.Contains(err.Error(), "unknown token eNOPE") {
+		t.Errorf("unknown symbol: error = %v", err)
+	}
+}
+
+func TestResolveReadsTheChain(t *testing.T) {
+	ctx := context.Background()
+	chain := newResolverChain(t)
+
+	// A token registered without decimals keeps its symbol and gets its decimals from the contract
+	tokens := New()
+	if err := tokens.Add(Token{Symbol: "JPY", Address: chain.jpy, Source: "tokens.yaml"}); err != nil {
+		t.Fatal(err)
+	}
+	r := chain.resolver(tokens)
+
+	jpy, err := r.Resolve(ctx, "jpy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if jpy.Symbol != "JPY" || jpy.Decimals != 8 || jpy.Source != "tokens.yaml" {
+		t.Errorf("JPY = %+v", jpy)
+	}
+
+	// An unregistered address is read in full
+	usd, err := r.Resolve(ctx, chain.usd6.Hex())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if usd.Symbol != "eUSD" || usd.Decimals != 6 || usd.Source != OnChainSource {
+		t.Errorf("eUSD at %s = %+v", chain.usd6.Hex(), usd)
+	}
+
+	// A symbol only the pools use is found through the pool reader
+	gbp, err := r.Resolve(ctx, "eGBP")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gbp.Address != chain.gbp || gbp.Decimals != 18 {
+		t.Errorf("eGBP = %+v", gbp)
+	}
+
+	if _, err := r.Resolve(ctx, chain.Account().Hex()); err == nil {
+		t.Error("an account without code resolved as a token")
+	}
+}
+
+func TestResolveAmbiguousSymbols(t *testing.T) {
+	ctx := context.Background()
+	chain := newResolverChain(t)
+	r := chain.resolver(New())
+
+	// Both pools trade an eUSD, at different addresses
+	_, err := r.Resolve(ctx, "eUSD")
+	if err == nil {
+		t.Fatal("an ambiguous symbol resolved")
+	}
+	for _, address := range []common.Address{chain.usd6, chain.usd18} {
+		if !strings.Contains(err.Error(), address.Hex()) {
+			t.Errorf("error %q does not list %s", err, address.Hex())
+		}
+	}
+
+	// The addresses still work
+	for _, address := range []common.Address{chain.usd6, chain.usd18} {
+		if token, err := r.Resolve(ctx, address.Hex()); err != nil || token.Address != address {
+			t.Errorf("%s = %+v, %v", address.Hex(), token, err)
+		}
+	}
+
+	// Pool registry tokens declared at two addresses are ambiguous without a chain as well
+	pools := registry.New()
+	for i, address := range []common.Address{addressA, addressB} {
+		pool := &registry.Pool{
+			Name:    []string{"A", "B"}[i],
+			Address: common.BigToAddress(big.NewInt(int64(0x100 + i))),
+			Token0:  registry.Token{Symbol: "eUSD", Address: address, Decimals: 6},
+			Token1:  registry.Token{Symbol: "eEUR", Address: common.HexToAddress("0x0e"), Decimals: 6},
+		}
+		if err := pools.Add(pool); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tokens, err := Load("", pools)
+	if err != nil {
+		t.Fatal(err)
+	}
+	offline := NewResolver(Options{Tokens: tokens, Pools: pools})
+	if _, err := offline.Resolve(ctx, "eusd"); err == nil || !strings.Contains(err.Error(), "give the address") {
+		t.Errorf("ambiguous registry symbol: error = %v", err)
+	}
+	if eur, err := offline.Resolve(ctx, "eEUR"); err != nil || eur.Decimals != 6 {
+		t.Errorf("eEUR = %+v, %v", eur, err)
+	}
+}
@@ -0,0 +1,41 @@
+package tokens
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+)
+
+// FormatUnits formats an amount in base units as a decimal number of whole tokens
+func FormatUnits(amount *big.Int, decimals uint8) string {
+	value := new(big.Rat).SetFrac(amount, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil))
+
+	formatted := value.FloatString(int(decimals))
+	if strings.Contains(formatted, ".") {
+		formatted = strings.TrimRight(strings.TrimRight(formatted, "0"), ".")
+	}
+
+	return formatted
+}
+
+// Format formats an amount of the token in base units with its symbol, like "1.5 USDC"
+func (t Token) Format(amount *big.Int) string {
+	return fmt.Sprintf("%s %s", FormatUnits(amount, t.Decimals), t.Symbol)
+}
+
+// ScaleRatio converts a reserve0/reserve1 ratio of base units into whole
+// tokens of a pool whose token0 and token1 have the given decimals. Pools
+// whose tokens have the same decimals need no scaling.
+func ScaleRatio(ratio float64, decimals0, decimals1 uint8) float64 {
+	if decimals0 == decimals1 {
+		return ratio
+	}
+
+	return ratio * math.Pow10(int(decimals1)-int(decimals0))
+}
+
+// UnscaleRatio converts a reserve0/reserve1 ratio of whole tokens back into base units
+func UnscaleRatio(ratio float64, decimals0, decimals1 uint8) float64 {
+	return ScaleRatio(ratio, decimals1, decimals0)
+}
@@ -13,11 +13,15 @@ var KeystoreCmd = &cobra.Command{
 
 func init() {
 	// Add persistent flags that will be available to all subcommands
-	KeystoreCmd.PersistentFlags().StringP("keystore-dir", "d", "./keystore", "Custom keystore directory")
+	KeystoreCmd.PersistentFlags().StringP("keystore-dir", "d", "", "Keystore directory (default $TRADEBOT_KEYSTORE_DIR, then keystore_dir from the config file, then ./keystore)")
 
 	// Add the wallet subcommands
 	KeystoreCmd.AddCommand(wallet.CreateWalletCmd)
-	// Future subcommands will be added here
-	// KeystoreCmd.AddCommand(wallet.ListWalletsCmd)
-	// KeystoreCmd.AddCommand(wallet.ImportWalletCmd)
+	KeystoreCmd.AddCommand(wallet.ListWalletsCmd)
+	KeystoreCmd.AddCommand(wallet.ImportWalletCmd)
+	KeystoreCmd.AddCommand(wallet.CreateHDCmd)
+	KeystoreCmd.AddCommand(wallet.DeriveHDCmd)
+	KeystoreCmd.AddCommand(wallet.ChangePasswordCmd)
+	KeystoreCmd.AddCommand(wallet.ExportCmd)
+	KeystoreCmd.AddCommand(wallet.MockSignerCmd)
 }
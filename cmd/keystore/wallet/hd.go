@@ -0,0 +1,246 @@
+package wallet
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/hdwallet"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/password"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/cobra"
+)
+
+var (
+	hdAccounts     int
+	hdWords        int
+	hdMnemonicFile string
+	hdPasswordFile string
+	hdWalletID     string
+	hdCount        int
+)
+
+// CreateHDCmd creates an HD wallet and derives its first accounts
+var CreateHDCmd = &cobra.Command{
+	Use:   "create-hd",
+	Short: "Create an HD wallet and derive trading accounts from it",
+	Long: `Generates a BIP-39 mnemonic (or restores one from --mnemonic-file), derives --accounts
+accounts along m/44'/60'/0'/0/i and stores each encrypted in --keystore-dir. The seed is
+kept encrypted in a manifest under <keystore-dir>/hd so derive-hd can add accounts later
+with the password alone.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		keystoreDir, err := resolveKeystoreDir(cmd)
+		if err != nil {
+			log.Fatalf("Error resolving keystore directory: %v", err)
+		}
+
+		if hdAccounts < 1 {
+			log.Fatalf("--accounts must be at least 1")
+		}
+
+		// Resolve the key derivation strength before asking for any secrets
+		scryptN, scryptP, err := scryptParams(cmd)
+		if err != nil {
+			log.Fatalf("Invalid KDF settings: %v", err)
+		}
+
+		// Generate a fresh mnemonic unless one is being restored
+		mnemonic := ""
+		if hdMnemonicFile != "" {
+			mnemonic, err = readSecret(hdMnemonicFile)
+		} else {
+			mnemonic, err = hdwallet.NewMnemonic(hdWords / 3 * 32)
+		}
+		if err != nil {
+			log.Fatalf("Error getting mnemonic: %v", err)
+		}
+
+		seed, err := hdwallet.Seed(mnemonic, "")
+		if err != nil {
+			log.Fatalf("Error reading mnemonic: %v", err)
+		}
+		master, err := hdwallet.NewMaster(seed)
+		if err != nil {
+			log.Fatalf("Error deriving master key: %v", err)
+		}
+
+		walletPassword, err := password.Read(password.Options{File: hdPasswordFile, Prompt: "Password for the HD wallet", Confirm: true})
+		if err != nil {
+			log.Fatalf("Error reading password: %v", err)
+		}
+		if err := validatePassword(walletPassword); err != nil {
+			log.Fatalf("Password validation failed: %v", err)
+		}
+
+		manifest, err := hdwallet.NewManifest(seed, walletPassword, scryptN, scryptP)
+		if err != nil {
+			log.Fatalf("Error encrypting seed: %v", err)
+		}
+
+		// The wallet is identified by its first account
+		_, firstPath, err := manifest.AccountPath(0)
+		if err != nil {
+			log.Fatalf("Error deriving first account: %v", err)
+		}
+		first, err := master.Derive(firstPath)
+		if err != nil {
+			log.Fatalf("Error deriving first account: %v", err)
+		}
+		firstKey, err := first.PrivateKey()
+		if err != nil {
+			log.Fatalf("Error deriving first account: %v", err)
+		}
+		manifest.ID = strings.ToLower(crypto.PubkeyToAddress(firstKey.PublicKey).Hex())
+
+		if _, err := os.Stat(hdwallet.ManifestPath(keystoreDir, manifest.ID)); err == nil {
+			log.Fatalf("HD wallet %s already exists in %s: use derive-hd to add accounts", manifest.ID, keystoreDir)
+		}
+
+		// Ensure the keystore directory exists
+		if err := os.MkdirAll(keystoreDir, 0o700); err != nil {
+			log.Fatalf("Error creating keystore directory: %v", err)
+		}
+
+		ks := keystore.NewKeyStore(keystoreDir, scryptN, scryptP)
+		deriveErr := deriveAccounts(ks, manifest, master, hdAccounts, walletPassword)
+
+		// Record whatever was derived, even if a later index failed
+		if err := manifest.Save(keystoreDir); err != nil {
+			log.Fatalf("Error saving manifest: %v", err)
+		}
+		if deriveErr != nil {
+			log.Fatalf("Error deriving accounts: %v", deriveErr)
+		}
+
+		fmt.Println("🎉 HD wallet created successfully!")
+		fmt.Println("🆔 Wallet ID:", manifest.ID)
+		fmt.Println("📁 Manifest saved to:", hdwallet.ManifestPath(keystoreDir, manifest.ID))
+		printKDF(scryptN, scryptP)
+		if hdMnemonicFile == "" {
+			fmt.Println("\n⚠️ Write down this mnemonic and keep it offline. It is shown only once")
+			fmt.Println("   and recovers every account of this wallet:")
+			fmt.Printf("\n   %s\n", mnemonic)
+		}
+	},
+}
+
+// DeriveHDCmd derives more accounts from an existing HD wallet
+var DeriveHDCmd = &cobra.Command{
+	Use:   "derive-hd",
+	Short: "Derive more accounts from an HD wallet",
+	Long:  `Unlocks the encrypted seed of an HD wallet created by create-hd with its password and derives --count more accounts after the last one derived.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		keystoreDir, err := resolveKeystoreDir(cmd)
+		if err != nil {
+			log.Fatalf("Error resolving keystore directory: %v", err)
+		}
+
+		if hdCount < 1 {
+			log.Fatalf("--count must be at least 1")
+		}
+
+		// Resolve the key derivation strength before asking for any secrets
+		scryptN, scryptP, err := scryptParams(cmd)
+		if err != nil {
+			log.Fatalf("Invalid KDF settings: %v", err)
+		}
+
+		manifest, err := hdwallet.LoadManifest(keystoreDir, hdWalletID)
+		if err != nil {
+			log.Fatalf("Error loading HD wallet %s: %v", hdWalletID, err)
+		}
+
+		walletPassword, err := password.Read(password.Options{File: hdPasswordFile, Prompt: "Password for the HD wallet"})
+		if err != nil {
+			log.Fatalf("Error reading password: %v", err)
+		}
+
+		master, err := manifest.Unlock(walletPassword)
+		if err != nil {
+			log.Fatalf("Error unlocking HD wallet: %v", err)
+		}
+
+		ks := keystore.NewKeyStore(keystoreDir, scryptN, scryptP)
+		deriveErr := deriveAccounts(ks, manifest, master, hdCount, walletPassword)
+
+		if err := manifest.Save(keystoreDir); err != nil {
+			log.Fatalf("Error saving manifest: %v", err)
+		}
+		if deriveErr != nil {
+			log.Fatalf("Error deriving accounts: %v", deriveErr)
+		}
+
+		fmt.Printf("🎉 Derived %d more account(s) from HD wallet %s\n", hdCount, manifest.ID)
+		printKDF(scryptN, scryptP)
+	},
+}
+
+func init() {
+	CreateHDCmd.Flags().IntVar(&hdAccounts, "accounts", 5, "Number of accounts to derive")
+	CreateHDCmd.Flags().IntVar(&hdWords, "words", 12, "Mnemonic length: 12 or 24 words")
+	CreateHDCmd.Flags().StringVar(&hdMnemonicFile, "mnemonic-file", "", "Restore from a mnemonic in this file (\"-\" for stdin) instead of generating one")
+	CreateHDCmd.Flags().StringVar(&hdPasswordFile, "password-file", "", "File containing the wallet password")
+	CreateHDCmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+		if hdWords != 12 && hdWords != 24 {
+			return fmt.Errorf("--words must be 12 or 24")
+		}
+		return nil
+	}
+
+	DeriveHDCmd.Flags().StringVar(&hdWalletID, "id", "", "HD wallet ID (the address of its first account)")
+	DeriveHDCmd.Flags().IntVar(&hdCount, "count", 1, "Number of accounts to derive")
+	DeriveHDCmd.Flags().StringVar(&hdPasswordFile, "password-file", "", "File containing the wallet password")
+	DeriveHDCmd.MarkFlagRequired("id")
+
+	addKDFFlags(CreateHDCmd)
+	addKDFFlags(DeriveHDCmd)
+}
+
+// deriveAccounts derives count accounts after the manifest's last index, stores each
+// in the keystore with the wallet password and records it in the manifest
+func deriveAccounts(ks *keystore.KeyStore, manifest *hdwallet.Manifest, master *hdwallet.Key, count int, walletPassword string) error {
+	index := manifest.NextIndex()
+	for derived := 0; derived < count; index++ {
+		path, parsed, err := manifest.AccountPath(index)
+		if err != nil {
+			return err
+		}
+
+		child, err := master.Derive(parsed)
+		if err == hdwallet.ErrInvalidKey {
+			// BIP-32: an index that yields an invalid key is skipped
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		key, err := child.PrivateKey()
+		if err != nil {
+			return err
+		}
+		address := crypto.PubkeyToAddress(key.PublicKey)
+
+		if ks.HasAddress(address) {
+			return fmt.Errorf("account %s at %s is already in the keystore", address.Hex(), path)
+		}
+
+		account, err := ks.ImportECDSA(key, walletPassword)
+		if err != nil {
+			return fmt.Errorf("storing %s: %w", path, err)
+		}
+
+		manifest.Accounts = append(manifest.Accounts, hdwallet.Account{
+			Index:   index,
+			Path:    path,
+			Address: account.Address,
+			File:    account.URL.Path,
+		})
+		fmt.Printf("📝 %s  %s\n", path, account.Address.Hex())
+		derived++
+	}
+
+	return nil
+}
@@ -0,0 +1,188 @@
+package wallet
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/hdwallet"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/password"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/cobra"
+)
+
+var (
+	importKeyFile         string
+	importKeystoreJSON    string
+	importOldPasswordFile string
+	importMnemonicFile    string
+	importPath            string
+	importPasswordFile    string
+)
+
+// ImportWalletCmd imports an existing account into the keystore directory
+var ImportWalletCmd = &cobra.Command{
+	Use:   "import-wallet",
+	Short: "Import an existing wallet",
+	Long: `Imports an existing account into --keystore-dir from exactly one of:
+  --private-key-file  a hex private key
+  --keystore-json     a V3 keystore JSON file, unlocked with its old password
+  --mnemonic-file     a BIP-39 mnemonic, derived along --path
+Secrets are read from files ("-" for stdin), never from the command line. The account
+is re-encrypted with a new password that must pass the same rules as create-wallet.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		keystoreDir, err := resolveKeystoreDir(cmd)
+		if err != nil {
+			log.Fatalf("Error resolving keystore directory: %v", err)
+		}
+
+		// Resolve the key derivation strength before asking for any secrets
+		scryptN, scryptP, err := scryptParams(cmd)
+		if err != nil {
+			log.Fatalf("Invalid KDF settings: %v", err)
+		}
+
+		sources := 0
+		for _, source := range []string{importKeyFile, importKeystoreJSON, importMnemonicFile} {
+			if source != "" {
+				sources++
+			}
+		}
+		if sources != 1 {
+			log.Fatalf("Give exactly one of --private-key-file, --keystore-json or --mnemonic-file")
+		}
+
+		// Recover the private key from whichever source was given
+		var key *ecdsa.PrivateKey
+		switch {
+		case importKeyFile != "":
+			key, err = importPrivateKey(importKeyFile)
+		case importKeystoreJSON != "":
+			key, err = importKeystore(importKeystoreJSON, importOldPasswordFile)
+		default:
+			key, err = importMnemonic(importMnemonicFile, importPath)
+		}
+		if err != nil {
+			log.Fatalf("Import failed: %v", err)
+		}
+		address := crypto.PubkeyToAddress(key.PublicKey)
+
+		// Ensure the keystore directory exists
+		if err := os.MkdirAll(keystoreDir, 0o700); err != nil {
+			log.Fatalf("Error creating keystore directory: %v", err)
+		}
+
+		ks := keystore.NewKeyStore(keystoreDir, scryptN, scryptP)
+		if ks.HasAddress(address) {
+			log.Fatalf("Wallet %s is already in %s", address.Hex(), keystoreDir)
+		}
+
+		newPassword, err := password.Read(password.Options{File: importPasswordFile, Prompt: "New keystore password", Confirm: true})
+		if err != nil {
+			log.Fatalf("Error reading new password: %v", err)
+		}
+		if err := validatePassword(newPassword); err != nil {
+			log.Fatalf("Password validation failed: %v", err)
+		}
+
+		account, err := ks.ImportECDSA(key, newPassword)
+		if err != nil {
+			log.Fatalf("Failed to import account: %v", err)
+		}
+
+		fmt.Println("🎉 Wallet imported successfully!")
+		fmt.Println("📁 Keystore saved to:", account.URL.Path)
+		fmt.Println("📝 Address:", account.Address.Hex())
+		printKDF(scryptN, scryptP)
+	},
+}
+
+func init() {
+	ImportWalletCmd.Flags().StringVar(&importKeyFile, "private-key-file", "", "File containing a hex private key (\"-\" for stdin)")
+	ImportWalletCmd.Flags().StringVar(&importKeystoreJSON, "keystore-json", "", "V3 keystore JSON file to import")
+	ImportWalletCmd.Flags().StringVar(&importOldPasswordFile, "old-password-file", "", "File containing the password of --keystore-json (prompted otherwise)")
+	ImportWalletCmd.Flags().StringVar(&importMnemonicFile, "mnemonic-file", "", "File containing a BIP-39 mnemonic (\"-\" for stdin)")
+	ImportWalletCmd.Flags().StringVar(&importPath, "path", hdwallet.DefaultPath, "Derivation path for --mnemonic-file")
+	ImportWalletCmd.Flags().StringVar(&importPasswordFile, "password-file", "", "File containing the new keystore password")
+	addKDFFlags(ImportWalletCmd)
+}
+
+// importPrivateKey reads a hex private key, with or without a 0x prefix
+func importPrivateKey(path string) (*ecdsa.PrivateKey, error) {
+	secret, err := readSecret(path)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(secret, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+
+	return key, nil
+}
+
+// importKeystore decrypts an existing keystore JSON file with its old password
+func importKeystore(path, oldPasswordFile string) (*ecdsa.PrivateKey, error) {
+	keyJSON, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var oldPassword string
+	if oldPasswordFile != "" {
+		oldPassword, err = password.FromFile(oldPasswordFile)
+	} else {
+		oldPassword, err = password.Prompt("Password of the keystore being imported")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := keystore.DecryptKey(keyJSON, oldPassword)
+	if err != nil {
+		return nil, fmt.Errorf("decrypting %s: %w", path, err)
+	}
+
+	return key.PrivateKey, nil
+}
+
+// importMnemonic derives the key at a derivation path from a BIP-39 mnemonic
+func importMnemonic(path, derivationPath string) (*ecdsa.PrivateKey, error) {
+	mnemonic, err := readSecret(path)
+	if err != nil {
+		return nil, err
+	}
+
+	seed, err := hdwallet.Seed(mnemonic, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return hdwallet.DeriveKey(seed, derivationPath)
+}
+
+// readSecret reads a secret from a file, or from stdin when path is "-"
+func readSecret(path string) (string, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	secret := strings.TrimSpace(string(data))
+	if secret == "" {
+		return "", fmt.Errorf("no secret found in %s", path)
+	}
+
+	return secret, nil
+}
@@ -0,0 +1,69 @@
+package wallet
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/spf13/cobra"
+)
+
+var (
+	kdfStrength string
+	kdfScryptN  int
+	kdfScryptP  int
+)
+
+// Bounds on custom scrypt parameters. Memory use is 128 * 8 * N bytes, so the
+// largest N needs 2 GiB; N below 2^10 offers no real protection.
+const (
+	minScryptN = 1 << 10
+	maxScryptN = 1 << 21
+	maxScryptP = 64
+)
+
+// addKDFFlags adds the --kdf, --scrypt-n and --scrypt-p flags to a command that encrypts keys
+func addKDFFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&kdfStrength, "kdf", "standard", "Key derivation strength: light, standard or custom")
+	cmd.Flags().IntVar(&kdfScryptN, "scrypt-n", 0, "scrypt N (CPU/memory cost) for --kdf custom, a power of two")
+	cmd.Flags().IntVar(&kdfScryptP, "scrypt-p", 0, "scrypt P (parallelism) for --kdf custom")
+}
+
+// scryptParams returns the scrypt N and P selected by the KDF flags
+func scryptParams(cmd *cobra.Command) (int, int, error) {
+	custom := cmd.Flags().Changed("scrypt-n") || cmd.Flags().Changed("scrypt-p")
+	if custom && kdfStrength != "custom" {
+		return 0, 0, fmt.Errorf("--scrypt-n and --scrypt-p require --kdf custom")
+	}
+
+	switch kdfStrength {
+	case "light":
+		return keystore.LightScryptN, keystore.LightScryptP, nil
+	case "standard":
+		return keystore.StandardScryptN, keystore.StandardScryptP, nil
+	case "custom":
+		if err := validateScrypt(kdfScryptN, kdfScryptP); err != nil {
+			return 0, 0, err
+		}
+		return kdfScryptN, kdfScryptP, nil
+	default:
+		return 0, 0, fmt.Errorf("unknown --kdf %q: use light, standard or custom", kdfStrength)
+	}
+}
+
+// validateScrypt checks custom scrypt parameters
+func validateScrypt(n, p int) error {
+	if n < minScryptN || n > maxScryptN || n&(n-1) != 0 {
+		return fmt.Errorf("--scrypt-n must be a power of two between %d and %d, got %d", minScryptN, maxScryptN, n)
+	}
+
+	if p < 1 || p > maxScryptP {
+		return fmt.Errorf("--scrypt-p must be between 1 and %d, got %d", maxScryptP, p)
+	}
+
+	return nil
+}
+
+// printKDF reports the scrypt parameters a key is encrypted with
+func printKDF(n, p int) {
+	fmt.Printf("🔐 KDF: scrypt N=%d P=%d (%s)\n", n, p, kdfStrength)
+}
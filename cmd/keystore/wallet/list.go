@@ -0,0 +1,214 @@
+package wallet
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/constants"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/contracts/erc20"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/tokens"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/spf13/cobra"
+)
+
+var (
+	listFormat   string
+	listBalances bool
+	listRPCURL   string
+	listTokens   []string
+)
+
+// walletInfo is one account found in the keystore directory
+type walletInfo struct {
+	Address  string            `json:"address"`
+	Path     string            `json:"path"`
+	Created  *time.Time        `json:"created,omitempty"`
+	Balances map[string]string `json:"balances,omitempty"`
+}
+
+// ListWalletsCmd lists the accounts in the keystore directory
+var ListWalletsCmd = &cobra.Command{
+	Use:   "list-wallets",
+	Short: "List the wallets in the keystore directory",
+	Long:  `Lists every account in --keystore-dir with its keystore file and creation time, and optionally its ETH and token balances.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		keystoreDir, err := resolveKeystoreDir(cmd)
+		if err != nil {
+			log.Fatalf("Error resolving keystore directory: %v", err)
+		}
+
+		if listFormat != "text" && listFormat != "json" {
+			log.Fatalf("Unknown format %q: use text or json", listFormat)
+		}
+
+		wallets, err := listWallets(keystoreDir)
+		if err != nil {
+			log.Fatalf("Error reading keystore directory: %v", err)
+		}
+
+		if listBalances {
+			if err := readBalances(cmd.Context(), wallets); err != nil {
+				log.Fatalf("Error reading balances: %v", err)
+			}
+		}
+
+		if listFormat == "json" {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(wallets); err != nil {
+				log.Fatalf("Error encoding wallets: %v", err)
+			}
+			return
+		}
+
+		if len(wallets) == 0 {
+			fmt.Println("No wallets found in", keystoreDir)
+			return
+		}
+
+		fmt.Printf("👛 %d wallet(s) in %s\n", len(wallets), keystoreDir)
+		for i, wallet := range wallets {
+			fmt.Printf("\n%d. 📝 Address: %s\n", i+1, wallet.Address)
+			fmt.Println("   📁 File:", wallet.Path)
+			if wallet.Created != nil {
+				fmt.Println("   🕒 Created:", wallet.Created.Format(time.RFC3339))
+			}
+			for _, token := range sortedKeys(wallet.Balances) {
+				fmt.Printf("   💰 %s: %s\n", token, wallet.Balances[token])
+			}
+		}
+	},
+}
+
+func init() {
+	ListWalletsCmd.Flags().StringVar(&listFormat, "format", "text", "Output format: text or json")
+	ListWalletsCmd.Flags().BoolVar(&listBalances, "balances", false, "Show on-chain ETH and token balances")
+	ListWalletsCmd.Flags().StringVarP(&listRPCURL, "rpc-url", "r", "https://eth-goerli.g.alchemy.com/v2/demo", "Ethereum RPC URL used for --balances")
+	ListWalletsCmd.Flags().StringSliceVar(&listTokens, "token", nil, "ERC-20 token address to show balances of (repeatable)")
+}
+
+// listWallets reads the accounts in a keystore directory, ordered by file name
+func listWallets(dir string) ([]*walletInfo, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return nil, err
+	}
+
+	ks := keystore.NewKeyStore(dir, keystore.StandardScryptN, keystore.StandardScryptP)
+	found := ks.Accounts()
+	sort.Sort(accounts.AccountsByURL(found))
+
+	wallets := make([]*walletInfo, 0, len(found))
+	for _, account := range found {
+		wallet := &walletInfo{
+			Address: account.Address.Hex(),
+			Path:    account.URL.Path,
+		}
+		if created, ok := keyFileTime(account.URL.Path); ok {
+			wallet.Created = &created
+		}
+		wallets = append(wallets, wallet)
+	}
+
+	return wallets, nil
+}
+
+// keyFileTime parses the creation time from a keystore file name such as
+// UTC--2024-01-02T15-04-05.000000000Z--<address>
+func keyFileTime(path string) (time.Time, bool) {
+	name, ok := strings.CutPrefix(filepath.Base(path), "UTC--")
+	if !ok {
+		return time.Time{}, false
+	}
+
+	stamp, _, ok := strings.Cut(name, "--")
+	if !ok {
+		return time.Time{}, false
+	}
+
+	created, err := time.Parse("2006-01-02T15-04-05.999999999Z", stamp)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return created, true
+}
+
+// readBalances fills in the ETH balance and each --token balance of every wallet
+func readBalances(ctx context.Context, wallets []*walletInfo) error {
+	client, err := ethclient.DialContext(ctx, listRPCURL)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	type token struct {
+		symbol   string
+		decimals uint8
+		caller   *erc20.IERC20Caller
+	}
+
+	opts := &bind.CallOpts{Context: ctx}
+	listed := make([]token, 0, len(listTokens))
+	for _, address := range listTokens {
+		if !common.IsHexAddress(address) {
+			return fmt.Errorf("invalid token address %q", address)
+		}
+
+		caller, err := erc20.NewIERC20Caller(common.HexToAddress(address), client)
+		if err != nil {
+			return err
+		}
+		symbol, err := caller.Symbol(opts)
+		if err != nil {
+			return fmt.Errorf("reading symbol of %s: %w", address, err)
+		}
+		decimals, err := caller.Decimals(opts)
+		if err != nil {
+			return fmt.Errorf("reading decimals of %s: %w", symbol, err)
+		}
+		listed = append(listed, token{symbol: symbol, decimals: decimals, caller: caller})
+	}
+
+	for _, wallet := range wallets {
+		address := common.HexToAddress(wallet.Address)
+		wallet.Balances = make(map[string]string, len(listed)+1)
+
+		balance, err := client.BalanceAt(ctx, address, nil)
+		if err != nil {
+			return fmt.Errorf("reading ETH balance of %s: %w", wallet.Address, err)
+		}
+		wallet.Balances["ETH"] = tokens.FormatUnits(balance, constants.NativeDecimals)
+
+		for _, token := range listed {
+			balance, err := token.caller.BalanceOf(opts, address)
+			if err != nil {
+				return fmt.Errorf("reading %s balance of %s: %w", token.symbol, wallet.Address, err)
+			}
+			wallet.Balances[token.symbol] = tokens.FormatUnits(balance, token.decimals)
+		}
+	}
+
+	return nil
+}
+
+// sortedKeys returns the keys of a map in alphabetical order
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,224 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/password"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/cobra"
+)
+
+var (
+	manageAddress         string
+	manageOldPasswordFile string
+	managePasswordFile    string
+	exportOut             string
+	exportUnencrypted     bool
+	exportConfirmRaw      bool
+)
+
+// ChangePasswordCmd re-encrypts a wallet with a new password
+var ChangePasswordCmd = &cobra.Command{
+	Use:   "change-password",
+	Short: "Change the password of a wallet",
+	Long:  `Decrypts the wallet with its current password and re-encrypts it with a new one. The keystore file is replaced atomically, so it keeps its address and is never left half written.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		keystoreDir, err := resolveKeystoreDir(cmd)
+		if err != nil {
+			log.Fatalf("Error resolving keystore directory: %v", err)
+		}
+
+		// Resolve the key derivation strength before asking for any secrets
+		scryptN, scryptP, err := scryptParams(cmd)
+		if err != nil {
+			log.Fatalf("Invalid KDF settings: %v", err)
+		}
+
+		// The keystore's scrypt parameters are the ones Update re-encrypts with
+		ks := keystore.NewKeyStore(keystoreDir, scryptN, scryptP)
+		account, err := findAccount(ks, manageAddress)
+		if err != nil {
+			log.Fatalf("Error finding wallet: %v", err)
+		}
+
+		oldPassword, err := readOldPassword(manageOldPasswordFile)
+		if err != nil {
+			log.Fatalf("Error reading current password: %v", err)
+		}
+
+		newPassword, err := readNewPassword(managePasswordFile, "New password", oldPassword)
+		if err != nil {
+			log.Fatalf("Error reading new password: %v", err)
+		}
+
+		// Update writes a temporary file, checks it decrypts, then renames it over the original
+		if err := ks.Update(account, oldPassword, newPassword); err != nil {
+			log.Fatalf("Error changing password: %v", err)
+		}
+
+		fmt.Println("🔑 Password changed successfully!")
+		fmt.Println("📁 Keystore:", account.URL.Path)
+		fmt.Println("📝 Address:", account.Address.Hex())
+		printKDF(scryptN, scryptP)
+	},
+}
+
+// ExportCmd writes a copy of a wallet, re-encrypted or as a raw private key
+var ExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export a wallet to a file",
+	Long: `Writes a copy of a wallet to --out, re-encrypted with a new password. With --unencrypted
+and --confirm-unencrypted it writes the raw hex private key instead. The file is created with
+0600 permissions and an existing file is never overwritten.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		keystoreDir, err := resolveKeystoreDir(cmd)
+		if err != nil {
+			log.Fatalf("Error resolving keystore directory: %v", err)
+		}
+
+		if exportUnencrypted && !exportConfirmRaw {
+			log.Fatalf("Exporting an unencrypted private key also requires --confirm-unencrypted")
+		}
+
+		// Resolve the key derivation strength before asking for any secrets
+		scryptN, scryptP, err := scryptParams(cmd)
+		if err != nil {
+			log.Fatalf("Invalid KDF settings: %v", err)
+		}
+
+		// The keystore's scrypt parameters are the ones Export re-encrypts with
+		ks := keystore.NewKeyStore(keystoreDir, scryptN, scryptP)
+		account, err := findAccount(ks, manageAddress)
+		if err != nil {
+			log.Fatalf("Error finding wallet: %v", err)
+		}
+
+		oldPassword, err := readOldPassword(manageOldPasswordFile)
+		if err != nil {
+			log.Fatalf("Error reading current password: %v", err)
+		}
+
+		var content []byte
+		if exportUnencrypted {
+			keyJSON, err := os.ReadFile(account.URL.Path)
+			if err != nil {
+				log.Fatalf("Error reading keystore: %v", err)
+			}
+			key, err := keystore.DecryptKey(keyJSON, oldPassword)
+			if err != nil {
+				log.Fatalf("Error decrypting wallet: %v", err)
+			}
+			content = []byte(hexutil.Encode(crypto.FromECDSA(key.PrivateKey))[2:] + "\n")
+		} else {
+			newPassword, err := readNewPassword(managePasswordFile, "Password for the exported copy", oldPassword)
+			if err != nil {
+				log.Fatalf("Error reading new password: %v", err)
+			}
+			if content, err = ks.Export(account, oldPassword, newPassword); err != nil {
+				log.Fatalf("Error exporting wallet: %v", err)
+			}
+		}
+
+		if err := writeNewFile(exportOut, content); err != nil {
+			log.Fatalf("Error writing %s: %v", exportOut, err)
+		}
+
+		if exportUnencrypted {
+			fmt.Println("⚠️ Unencrypted private key written to:", exportOut)
+			fmt.Println("   Anyone who can read this file controls the wallet. Delete it as soon as possible.")
+		} else {
+			fmt.Println("📦 Encrypted copy written to:", exportOut)
+			printKDF(scryptN, scryptP)
+		}
+		fmt.Println("📝 Address:", account.Address.Hex())
+	},
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{ChangePasswordCmd, ExportCmd} {
+		cmd.Flags().StringVar(&manageAddress, "address", "", "Address of the wallet (required)")
+		cmd.Flags().StringVar(&manageOldPasswordFile, "old-password-file", "", "File containing the wallet's current password")
+		cmd.MarkFlagRequired("address")
+	}
+
+	ChangePasswordCmd.Flags().StringVar(&managePasswordFile, "password-file", "", "File containing the new password")
+
+	ExportCmd.Flags().StringVar(&managePasswordFile, "password-file", "", "File containing the password for the exported copy")
+	ExportCmd.Flags().StringVarP(&exportOut, "out", "o", "", "File to write the export to (required)")
+	ExportCmd.Flags().BoolVar(&exportUnencrypted, "unencrypted", false, "Export the raw private key instead of an encrypted copy")
+	ExportCmd.Flags().BoolVar(&exportConfirmRaw, "confirm-unencrypted", false, "Confirm that the raw private key should be written to disk")
+	ExportCmd.MarkFlagRequired("out")
+
+	addKDFFlags(ChangePasswordCmd)
+	addKDFFlags(ExportCmd)
+}
+
+// findAccount finds the keystore file of an address
+func findAccount(ks *keystore.KeyStore, address string) (accounts.Account, error) {
+	if !common.IsHexAddress(address) {
+		return accounts.Account{}, fmt.Errorf("invalid address %q", address)
+	}
+
+	account, err := ks.Find(accounts.Account{Address: common.HexToAddress(address)})
+	if err != nil {
+		return accounts.Account{}, fmt.Errorf("%s: %w", address, err)
+	}
+
+	return account, nil
+}
+
+// readOldPassword reads the current password of a wallet from a file or a prompt
+func readOldPassword(path string) (string, error) {
+	if path != "" {
+		return password.FromFile(path)
+	}
+
+	return password.Prompt("Current password")
+}
+
+// readNewPassword reads a new password from a file or a confirmed prompt, never
+// from the environment, and checks it is valid and differs from the old one
+func readNewPassword(path, prompt, oldPassword string) (string, error) {
+	var newPassword string
+	var err error
+	if path != "" {
+		newPassword, err = password.FromFile(path)
+	} else {
+		newPassword, err = password.PromptConfirm(prompt)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if err := validatePassword(newPassword); err != nil {
+		return "", fmt.Errorf("password validation failed: %w", err)
+	}
+	if newPassword == oldPassword {
+		return "", errors.New("the new password is the same as the current one")
+	}
+
+	return newPassword, nil
+}
+
+// writeNewFile writes content to a file that must not exist yet, readable only by the owner
+func writeNewFile(path string, content []byte) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+
+	if _, err := file.Write(content); err != nil {
+		file.Close()
+		os.Remove(path)
+		return err
+	}
+
+	return errors.Join(file.Sync(), file.Close())
+}
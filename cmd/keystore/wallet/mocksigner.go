@@ -0,0 +1,104 @@
+package wallet
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/password"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/signer"
+	"github.com/spf13/cobra"
+)
+
+var (
+	mockAddress      string
+	mockListen       string
+	mockPasswordFile string
+)
+
+// MockSignerCmd serves a wallet over Clef's external signer API for local testing
+var MockSignerCmd = &cobra.Command{
+	Use:   "mock-signer",
+	Short: "Serve a wallet as a local mock external signer",
+	Long: `Unlocks a wallet from --keystore-dir and serves it over the account_* JSON-RPC API of
+go-ethereum's Clef, so the --signer-url path of the trading commands can be tried locally.
+Unlike Clef it approves every request without asking, so it only listens on loopback addresses.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		keystoreDir, err := resolveKeystoreDir(cmd)
+		if err != nil {
+			log.Fatalf("Error resolving keystore directory: %v", err)
+		}
+
+		keystoreFile, err := signer.FindKeystore(keystoreDir, mockAddress)
+		if err != nil {
+			log.Fatalf("Error finding wallet: %v", err)
+		}
+
+		walletPassword, err := password.Read(password.Options{File: mockPasswordFile, Prompt: fmt.Sprintf("Password for %s", keystoreFile)})
+		if err != nil {
+			log.Fatalf("Error reading password: %v", err)
+		}
+
+		key, err := signer.NewKeystore(keystoreFile, walletPassword)
+		if err != nil {
+			log.Fatalf("Error unlocking wallet: %v", err)
+		}
+
+		listener, err := net.Listen("tcp", mockListen)
+		if err != nil {
+			log.Fatalf("Error listening on %s: %v", mockListen, err)
+		}
+
+		// Stop serving on Ctrl-C
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+		defer stop()
+		go func() {
+			<-ctx.Done()
+			listener.Close()
+		}()
+
+		requests := make(chan signer.MockRequest)
+		go logMockRequests(ctx, requests)
+
+		fmt.Println("🧪 Mock signer for", key.Address().Hex())
+		fmt.Printf("🔌 Listening on http://%s (use --signer-url http://%s)\n", listener.Addr(), listener.Addr())
+		fmt.Println("⚠️ Every signing request is approved. Do not use this with real funds.")
+
+		if err := signer.ServeMock(listener, key, requests); err != nil {
+			log.Fatalf("Mock signer stopped: %v", err)
+		}
+		fmt.Println("👋 Mock signer stopped")
+	},
+}
+
+func init() {
+	MockSignerCmd.Flags().StringVar(&mockAddress, "address", "", "Address of the wallet to serve (required)")
+	MockSignerCmd.Flags().StringVar(&mockListen, "listen", "127.0.0.1:8550", "Loopback address to listen on")
+	MockSignerCmd.Flags().StringVar(&mockPasswordFile, "password-file", "", "File containing the wallet password")
+	MockSignerCmd.MarkFlagRequired("address")
+}
+
+// logMockRequests prints every transaction the mock signer signs or refuses
+func logMockRequests(ctx context.Context, requests <-chan signer.MockRequest) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case request := <-requests:
+			if request.Err != nil {
+				fmt.Printf("❌ Refused: %v\n", request.Err)
+				continue
+			}
+			tx := request.Tx
+			to := "contract creation"
+			if tx.To() != nil {
+				to = tx.To().Hex()
+			}
+			fmt.Printf("✍️ Signed %s: nonce %d, to %s, value %s, gas %d, %d bytes of data\n",
+				tx.Hash().Hex(), tx.Nonce(), to, tx.Value(), tx.Gas(), len(tx.Data()))
+		}
+	}
+}
@@ -6,48 +6,52 @@ import (
 	"os"
 	"strings"
 
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/config"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/password"
 	"github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/spf13/cobra"
 )
 
-var (
-	walletPassword string
-	outputDir      string
-)
-
-// TODO: Update wallet.go to properly export its command:
-/*
-The key changes to be made are:
-
-Removed the direct keystore creation from the keystore.go command - it's now just a parent command
-Exported the commands by capitalizing their names (KeystoreCmd and CreateWalletCmd)
-Moved the keystore directory flag to be persistent in the parent command
-Updated the wallet command to use the persistent keystore directory flag
-Removed the duplicate outputDir flag since we're using the persistent keystore-dir flag
-*/
+var passwordFile string
 
 // Export the command variable
 var CreateWalletCmd = &cobra.Command{
 	Use:   "create-wallet",
 	Short: "Generate a new wallaet",
-	Long:  `Creates a new wallet with the provided password and saves it`,
+	Long:  `Creates a new wallet in --keystore-dir and saves it encrypted with a password read from --password-file or a hidden prompt with confirmation`,
 	Run: func(cmd *cobra.Command, args []string) {
+		keystoreDir, err := resolveKeystoreDir(cmd)
+		if err != nil {
+			log.Fatalf("Error resolving keystore directory: %v", err)
+		}
+
+		// Resolve the key derivation strength before asking for any secrets
+		scryptN, scryptP, err := scryptParams(cmd)
+		if err != nil {
+			log.Fatalf("Invalid KDF settings: %v", err)
+		}
+
+		// Read the password without it ever appearing on the command line
+		walletPassword, err := password.Read(password.Options{File: passwordFile, Prompt: "Password for the new wallet", Confirm: true})
+		if err != nil {
+			log.Fatalf("Error reading password: %v", err)
+		}
 
 		// Validate password strength
 		if err := validatePassword(walletPassword); err != nil {
 			log.Fatalf("Password validation failed: %v", err)
 		}
 
-		// Ensure the keystore directory exists
-		err := os.MkdirAll(outputDir, os.ModePerm)
+		// Ensure the keystore directory exists, private to the current user
+		err = os.MkdirAll(keystoreDir, 0o700)
 		if err != nil {
 			log.Fatalf("Error creating keystore directory: %v", err)
 		}
 
 		// Initilize keystore manager
-		ks := keystore.NewKeyStore(outputDir, keystore.StandardScryptN, keystore.StandardScryptP)
+		ks := keystore.NewKeyStore(keystoreDir, scryptN, scryptP)
 
 		// Create a new account
 		account, err := ks.NewAccount(walletPassword)
@@ -58,6 +62,7 @@ var CreateWalletCmd = &cobra.Command{
 		fmt.Println("🎉 New Wallet created successfully!")
 		fmt.Println("📁 Keystore saved to:", account.URL.Path)
 		fmt.Println("📝 Address:", account.Address.Hex())
+		printKDF(scryptN, scryptP)
 
 		// Load the key from the keystore to display the public key
 		keyJSON, err := os.ReadFile(account.URL.Path)
@@ -83,11 +88,17 @@ var CreateWalletCmd = &cobra.Command{
 
 func init() {
 	// add flags to the create-wallet command
-	CreateWalletCmd.Flags().StringVarP(&walletPassword, "password", "p", "", "Password for the new wallet (required)")
-	CreateWalletCmd.Flags().StringVarP(&outputDir, "output", "o", "./keystore", "Directory to store the keystore file")
+	CreateWalletCmd.Flags().StringVar(&passwordFile, "password-file", "", "File containing the password for the new wallet")
+	addKDFFlags(CreateWalletCmd)
+}
+
+// resolveKeystoreDir returns the keystore directory from --keystore-dir, the
+// environment or the --config file, after checking its permissions
+func resolveKeystoreDir(cmd *cobra.Command) (string, error) {
+	keystoreDir, _ := cmd.Flags().GetString("keystore-dir")
+	configPath, _ := cmd.Flags().GetString("config")
 
-	// Mark password as required
-	CreateWalletCmd.MarkFlagRequired("password")
+	return config.KeystoreDir(keystoreDir, configPath)
 }
 
 func validatePassword(password string) error {
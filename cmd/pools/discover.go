@@ -0,0 +1,309 @@
+package pools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/constants"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/config"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/discovery"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/registry"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/spf13/cobra"
+)
+
+var (
+	discoverRPCURL       string
+	discoverFactory      string
+	discoverDEX          string
+	discoverTokens       []string
+	discoverMinLiquidity float64
+	discoverStart        uint64
+	discoverLimit        uint64
+	discoverWatch        bool
+	discoverPollInterval time.Duration
+	discoverOut          string
+	discoverDryRun       bool
+)
+
+// DiscoverCmd adds the pairs of a Uniswap V2 factory to the pool registry file
+var DiscoverCmd = &cobra.Command{
+	Use:   "discover",
+	Short: "Find Uniswap V2 pairs from the factory and add them to the registry file",
+	Long: `Reads the factory's pairs with allPairsLength and allPairs, starting at index --start, and
+reads each pair's token symbols, decimals and reserves. Pairs passing the --token allowlist and
+--min-liquidity filters are added to the pool registry file, so the trading commands can scan them
+without a rebuild. With --watch it then keeps listening for PairCreated events and adds new pairs
+as they are deployed.
+
+Pairs already in the registry are left alone. The file written is --out, or else the registry file
+given by --pools-file, TRADEBOT_POOLS_FILE or pools_file in the config file; it is created when
+missing and rewritten otherwise, which drops its comments.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if !common.IsHexAddress(discoverFactory) {
+			log.Fatalf("Invalid factory address %q", discoverFactory)
+		}
+		feeBps, ok := constants.DEXFeeBps[discoverDEX]
+		if !ok {
+			log.Fatalf("Unknown DEX %q: it has no default fee", discoverDEX)
+		}
+		if discoverMinLiquidity < 0 {
+			log.Fatalf("--min-liquidity must not be negative")
+		}
+
+		path, err := discoverPath(cmd)
+		if err != nil {
+			log.Fatalf("Error resolving pool registry file: %v", err)
+		}
+		if path == "" && !discoverDryRun {
+			log.Fatalf("No pool registry file to write: pass --out or --pools-file, or set %s or pools_file in the config file", config.EnvPoolsFile)
+		}
+
+		file, pools, err := readRegistryFile(path)
+		if err != nil {
+			log.Fatalf("Error loading pool registry: %v", err)
+		}
+
+		// Stop on Ctrl-C, keeping the pools found so far
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+		defer stop()
+
+		client, err := ethclient.DialContext(ctx, discoverRPCURL)
+		if err != nil {
+			log.Fatalf("Error connecting to %s: %v", discoverRPCURL, err)
+		}
+		defer client.Close()
+
+		factory, err := discovery.NewFactory(common.HexToAddress(discoverFactory), client)
+		if err != nil {
+			log.Fatalf("Error binding factory: %v", err)
+		}
+
+		// Read the head before the pairs so --watch misses nothing created meanwhile
+		head, err := client.BlockNumber(ctx)
+		if err != nil {
+			log.Fatalf("Error reading block number: %v", err)
+		}
+
+		count, err := factory.PairCount(ctx)
+		if err != nil {
+			log.Fatalf("Error reading factory: %v", err)
+		}
+
+		verbose, _ := cmd.Flags().GetBool("verbose")
+		d := &discoverer{
+			factory: factory,
+			filter:  discovery.Filter{Tokens: discoverTokens, MinLiquidity: discoverMinLiquidity},
+			dex:     discoverDEX,
+			feeBps:  feeBps,
+			file:    file,
+			pools:   pools,
+			path:    path,
+			verbose: verbose,
+		}
+
+		end := count
+		if discoverLimit > 0 && discoverStart+discoverLimit < count {
+			end = discoverStart + discoverLimit
+		}
+
+		fmt.Printf("🏭 Factory %s has %d pair(s)\n", factory.Address().Hex(), count)
+		if discoverStart < end {
+			fmt.Printf("🔎 Checking pairs #%d to #%d\n", discoverStart, end-1)
+		}
+
+		checked := 0
+		for index := discoverStart; index < end; index++ {
+			address, err := factory.PairAt(ctx, index)
+			if err != nil {
+				if ctx.Err() == nil {
+					fmt.Printf("❌ Error reading pair #%d: %v\n", index, err)
+				}
+				break
+			}
+
+			d.consider(ctx, address)
+			checked++
+			if checked%100 == 0 {
+				fmt.Printf("⏳ %d/%d pair(s) checked, %d added\n", checked, end-discoverStart, d.added)
+			}
+		}
+
+		fmt.Printf("📊 Checked %d pair(s): %d added, %d already registered, %d filtered out, %d skipped\n",
+			checked, d.added, d.known, d.filtered, d.skipped)
+		d.save()
+
+		if !discoverWatch || ctx.Err() != nil {
+			return
+		}
+
+		fmt.Printf("👀 Watching for new pairs from block %d (Ctrl-C to stop)\n", head+1)
+
+		created := make(chan discovery.Created)
+		watchErr := make(chan error, 1)
+		go func() {
+			watchErr <- factory.WatchCreated(ctx, head+1, discoverPollInterval, created)
+		}()
+
+		for {
+			select {
+			case pair := <-created:
+				fmt.Printf("🆕 Pair %s created in block %d\n", pair.Pair.Hex(), pair.Block)
+				if d.consider(ctx, pair.Pair) {
+					d.save()
+				}
+			case err := <-watchErr:
+				if ctx.Err() == nil {
+					log.Fatalf("Error watching factory: %v", err)
+				}
+				fmt.Println("👋 Stopped watching")
+				return
+			}
+		}
+	},
+}
+
+func init() {
+	DiscoverCmd.Flags().StringVarP(&discoverRPCURL, "rpc-url", "r", "https://eth-goerli.g.alchemy.com/v2/demo", "Ethereum RPC URL")
+	DiscoverCmd.Flags().StringVar(&discoverFactory, "factory", constants.UniV2Factory, "Uniswap V2 factory address")
+	DiscoverCmd.Flags().StringVar(&discoverDEX, "dex", constants.DefaultDEX, "DEX the factory belongs to, which sets the pools' fee")
+	DiscoverCmd.Flags().StringSliceVar(&discoverTokens, "token", nil, "Allowed token symbol or address, repeatable; both tokens of a pair must be allowed")
+	DiscoverCmd.Flags().Float64Var(&discoverMinLiquidity, "min-liquidity", 0, "Minimum reserve of each token of a pair, in whole tokens")
+	DiscoverCmd.Flags().Uint64Var(&discoverStart, "start", 0, "Index of the first factory pair to check")
+	DiscoverCmd.Flags().Uint64Var(&discoverLimit, "limit", 1000, "Number of factory pairs to check, 0 for all")
+	DiscoverCmd.Flags().BoolVar(&discoverWatch, "watch", false, "Keep adding pairs from new PairCreated events")
+	DiscoverCmd.Flags().DurationVar(&discoverPollInterval, "poll-interval", 15*time.Second, "How often --watch polls for new pairs on HTTP endpoints")
+	DiscoverCmd.Flags().StringVarP(&discoverOut, "out", "o", "", "Pool registry file to write (default the resolved --pools-file)")
+	DiscoverCmd.Flags().BoolVar(&discoverDryRun, "dry-run", false, "Print the pairs that would be added without writing the file")
+}
+
+// discoverer registers the accepted pairs of a factory and counts the others
+type discoverer struct {
+	factory *discovery.Factory
+	filter  discovery.Filter
+	dex     string
+	feeBps  uint64
+	file    *registry.File
+	pools   *registry.Registry
+	path    string
+	verbose bool
+
+	added, unsaved, known, filtered, skipped int
+}
+
+// consider reads a pair and adds it to the registry when the filter accepts it,
+// reporting whether it was added. The reasons for leaving pairs out are only
+// printed with --verbose, since most of a factory's pairs are usually left out.
+func (d *discoverer) consider(ctx context.Context, address common.Address) bool {
+	if existing, ok := d.pools.ByAddress(address); ok {
+		d.known++
+		if d.verbose {
+			fmt.Printf("   ⏭️ %s is already registered as %s\n", address.Hex(), existing.Name)
+		}
+		return false
+	}
+
+	pair, err := d.factory.Inspect(ctx, address)
+	if err != nil {
+		d.skipped++
+		if d.verbose {
+			fmt.Printf("   ⚠️ Skipping %s: %v\n", address.Hex(), err)
+		}
+		return false
+	}
+
+	if err := d.filter.Check(pair); err != nil {
+		d.filtered++
+		if d.verbose {
+			fmt.Printf("   🚫 Filtered out %s/%s at %s: %v\n", pair.Token0.Symbol, pair.Token1.Symbol, address.Hex(), err)
+		}
+		return false
+	}
+
+	source := d.path
+	if source == "" {
+		source = "discovered"
+	}
+	pool, err := discovery.Pool(pair, d.dex, d.feeBps, d.pools, source)
+	if err == nil {
+		err = d.pools.Add(pool)
+	}
+	if err != nil {
+		d.skipped++
+		if d.verbose {
+			fmt.Printf("   ⚠️ Skipping %s: %v\n", address.Hex(), err)
+		}
+		return false
+	}
+
+	d.file.Pools = append(d.file.Pools, registry.NewFilePool(pool))
+	d.added++
+	d.unsaved++
+
+	fmt.Printf("✅ %s at %s: %g %s / %g %s\n", pool.Name, address.Hex(),
+		discovery.WholeTokens(pair.Reserves.Reserve0, pair.Token0.Decimals), pool.Token0.Symbol,
+		discovery.WholeTokens(pair.Reserves.Reserve1, pair.Token1.Decimals), pool.Token1.Symbol)
+
+	return true
+}
+
+// save writes the pools added since the last save to the registry file
+func (d *discoverer) save() {
+	if d.unsaved == 0 {
+		return
+	}
+	if discoverDryRun {
+		fmt.Printf("🧪 Dry run: %d pool(s) not written\n", d.unsaved)
+		d.unsaved = 0
+		return
+	}
+
+	if err := registry.WriteFile(d.path, d.file); err != nil {
+		log.Fatalf("Error writing pool registry: %v", err)
+	}
+	fmt.Printf("💾 Wrote %d new pool(s) to %s\n", d.unsaved, d.path)
+	d.unsaved = 0
+}
+
+// discoverPath resolves the registry file to write: --out, else the file the
+// trading commands read
+func discoverPath(cmd *cobra.Command) (string, error) {
+	if discoverOut != "" {
+		return discoverOut, nil
+	}
+
+	poolsFile, _ := cmd.Flags().GetString("pools-file")
+	configPath, _ := cmd.Flags().GetString("config")
+
+	return config.PoolsFile(poolsFile, configPath)
+}
+
+// readRegistryFile reads the registry file at path and the registry it gives. A
+// missing file, or no path, starts an empty file merged with the built-in pools.
+func readRegistryFile(path string) (*registry.File, *registry.Registry, error) {
+	if path == "" {
+		return &registry.File{}, registry.Builtin(), nil
+	}
+	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		return &registry.File{}, registry.Builtin(), nil
+	}
+
+	file, err := registry.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pools, err := file.Registry(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return file, pools, nil
+}
@@ -0,0 +1,119 @@
+package pools
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/registry"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+)
+
+var listFormat string
+
+// poolInfo is one registered pool as printed by --format json
+type poolInfo struct {
+	Name        string    `json:"name"`
+	Address     string    `json:"address"`
+	DEX         string    `json:"dex"`
+	FeeBps      uint64    `json:"fee_bps"`
+	Token0      tokenInfo `json:"token0"`
+	Token1      tokenInfo `json:"token1"`
+	TargetRatio float64   `json:"target_ratio,omitempty"`
+	Source      string    `json:"source"`
+}
+
+// tokenInfo is a pool token as printed by --format json; unknown fields are left out
+type tokenInfo struct {
+	Symbol   string `json:"symbol"`
+	Address  string `json:"address,omitempty"`
+	Decimals uint8  `json:"decimals,omitempty"`
+}
+
+// ListCmd prints the effective pool registry
+var ListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the registered pools",
+	Long:  `Prints every pool the trading commands will use after merging the registry file with the built-in pools, with its DEX, fee, tokens, target ratio and where it was defined.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if listFormat != "text" && listFormat != "json" {
+			log.Fatalf("Unknown format %q: use text or json", listFormat)
+		}
+
+		pools, path, err := openRegistry(cmd)
+		if err != nil {
+			log.Fatalf("Error loading pool registry: %v", err)
+		}
+
+		if listFormat == "json" {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			infos := make([]poolInfo, 0, pools.Len())
+			for _, pool := range pools.Pools() {
+				infos = append(infos, poolInfo{
+					Name:        pool.Name,
+					Address:     pool.Address.Hex(),
+					DEX:         pool.DEX,
+					FeeBps:      pool.FeeBps,
+					Token0:      newTokenInfo(pool.Token0),
+					Token1:      newTokenInfo(pool.Token1),
+					TargetRatio: pool.TargetRatio,
+					Source:      pool.Source,
+				})
+			}
+			if err := encoder.Encode(infos); err != nil {
+				log.Fatalf("Error encoding pools: %v", err)
+			}
+			return
+		}
+
+		if path != "" {
+			fmt.Printf("🗂️ %d pool(s) with registry file %s\n", pools.Len(), path)
+		} else {
+			fmt.Printf("🗂️ %d built-in pool(s)\n", pools.Len())
+		}
+
+		for i, pool := range pools.Pools() {
+			fmt.Printf("\n%d. %s\n", i+1, pool.Name)
+			fmt.Println("   📍 Address:", pool.Address.Hex())
+			fmt.Printf("   🏦 DEX: %s (fee %.2f%%)\n", pool.DEX, float64(pool.FeeBps)/100)
+			fmt.Println("   🪙 Token0:", describeToken(pool.Token0))
+			fmt.Println("   🪙 Token1:", describeToken(pool.Token1))
+			if pool.HasTarget() {
+				fmt.Printf("   🎯 Target Ratio: %g\n", pool.TargetRatio)
+			}
+			fmt.Println("   📄 Source:", pool.Source)
+		}
+	},
+}
+
+func init() {
+	ListCmd.Flags().StringVar(&listFormat, "format", "text", "Output format: text or json")
+}
+
+// newTokenInfo converts a registry token for JSON output
+func newTokenInfo(token registry.Token) tokenInfo {
+	info := tokenInfo{Symbol: token.Symbol, Decimals: token.Decimals}
+	if token.Address != (common.Address{}) {
+		info.Address = token.Address.Hex()
+	}
+
+	return info
+}
+
+// describeToken formats a registry token, marking what is left to be read on-chain
+func describeToken(token registry.Token) string {
+	address := "address read on-chain"
+	if token.Address != (common.Address{}) {
+		address = token.Address.Hex()
+	}
+
+	decimals := "decimals read on-chain"
+	if token.Decimals != 0 {
+		decimals = fmt.Sprintf("%d decimals", token.Decimals)
+	}
+
+	return fmt.Sprintf("%s (%s, %s)", token.Symbol, address, decimals)
+}
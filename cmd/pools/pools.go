@@ -0,0 +1,39 @@
+package pools
+
+import (
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/config"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/registry"
+	"github.com/spf13/cobra"
+)
+
+// PoolsCmd is the parent command for inspecting the pool registry
+var PoolsCmd = &cobra.Command{
+	Use:   "pools",
+	Short: "Inspect and extend the pool registry",
+	Long:  `The pools the trading commands use are the built-in defaults merged with an optional registry file given by --pools-file, the TRADEBOT_POOLS_FILE environment variable or pools_file in the config file.`,
+}
+
+func init() {
+	PoolsCmd.AddCommand(ListCmd)
+	PoolsCmd.AddCommand(DiscoverCmd)
+}
+
+// openRegistry loads the pool registry: the built-in pools merged with the file
+// given by --pools-file, the environment or the --config file. It also returns
+// the file's path, empty when only the built-in pools are used.
+func openRegistry(cmd *cobra.Command) (*registry.Registry, string, error) {
+	poolsFile, _ := cmd.Flags().GetString("pools-file")
+	configPath, _ := cmd.Flags().GetString("config")
+
+	path, err := config.PoolsFile(poolsFile, configPath)
+	if err != nil {
+		return nil, "", err
+	}
+
+	pools, err := registry.Load(path)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return pools, path, nil
+}
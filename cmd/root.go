@@ -5,6 +5,8 @@ import (
 	"os"
 
 	"github.com/AnnaGD/go-eth-trade-bot/cmd/keystore"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/pools"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/tokens"
 	"github.com/AnnaGD/go-eth-trade-bot/cmd/trade/arbitrage"
 	"github.com/AnnaGD/go-eth-trade-bot/cmd/trade"
 	"github.com/spf13/cobra"
@@ -31,6 +33,9 @@ func init() {
 
 	// Global persistent flags
 	rootCmd.PersistentFlags().Bool("verbose", false, "Enable verbose output")
+	rootCmd.PersistentFlags().String("config", "", "Config file (default $TRADEBOT_CONFIG, then tradebot/config.yaml in the user config directory)")
+	rootCmd.PersistentFlags().String("pools-file", "", "Pool registry file merged with the built-in pools (default $TRADEBOT_POOLS_FILE, then pools_file from the config file)")
+	rootCmd.PersistentFlags().String("tokens-file", "", "Token registry file merged with the built-in tokens (default $TRADEBOT_TOKENS_FILE, then tokens_file from the config file)")
 
 	// Keystore management for secret keys
 	rootCmd.AddCommand(keystore.KeystoreCmd)
@@ -40,4 +45,10 @@ func init() {
 
 	// Arbitrage command
 	rootCmd.AddCommand(arbitrage.ArbitrageCmd)
+
+	// Pool registry inspection
+	rootCmd.AddCommand(pools.PoolsCmd)
+
+	// Token allowances
+	rootCmd.AddCommand(tokens.TokensCmd)
 }
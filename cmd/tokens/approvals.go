@@ -0,0 +1,239 @@
+package tokens
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/constants"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/approval"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/cli"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/tokens"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/spf13/cobra"
+)
+
+// revokeAll is the --revoke value revoking every non-zero allowance
+const revokeAll = "all"
+
+var (
+	approvalsRPCURL  string
+	approvalsWallet  string
+	approvalsSpender string
+	approvalsRevoke  []string
+	approvalsFormat  string
+)
+
+// allowanceInfo is one non-zero allowance as printed by --format json
+type allowanceInfo struct {
+	Symbol    string `json:"symbol"`
+	Token     string `json:"token"`
+	Spender   string `json:"spender"`
+	Allowance string `json:"allowance"` // whole tokens, or "unlimited"
+	Revoked   string `json:"revoked,omitempty"`
+}
+
+// ApprovalsCmd lists, and optionally revokes, the wallet's allowances for the router
+var ApprovalsCmd = &cobra.Command{
+	Use:   "approvals",
+	Short: "List and revoke the wallet's token allowances for the router",
+	Long: `Reads the allowance --wallet has given --spender, the Uniswap V2 router by default, over every
+registered token and prints the non-zero ones.
+
+With --revoke, the listed tokens (by symbol or address, or "all" for every non-zero allowance) are
+approved down to zero, signed by --keystore-file, --signer-url or the --wallet account in the
+keystore directory.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		if approvalsFormat != "text" && approvalsFormat != "json" {
+			log.Fatalf("Unknown format %q: use text or json", approvalsFormat)
+		}
+		if !common.IsHexAddress(approvalsWallet) {
+			log.Fatalf("--wallet is required: the account whose allowances are listed")
+		}
+		if !common.IsHexAddress(approvalsSpender) {
+			log.Fatalf("Invalid spender address %q", approvalsSpender)
+		}
+		owner, spender := common.HexToAddress(approvalsWallet), common.HexToAddress(approvalsSpender)
+
+		pools, err := cli.OpenRegistry(cmd)
+		if err != nil {
+			log.Fatalf("Error loading pool registry: %v", err)
+		}
+		registered, err := cli.LoadTokens(cmd, pools)
+		if err != nil {
+			log.Fatalf("Error loading token registry: %v", err)
+		}
+
+		client, err := ethclient.DialContext(ctx, approvalsRPCURL)
+		if err != nil {
+			log.Fatalf("Error connecting to Ethereum: %v", err)
+		}
+		defer client.Close()
+		resolver := tokens.NewResolver(tokens.Options{Tokens: registered, Pools: pools, Caller: client})
+
+		revoke, err := revokedTokens(ctx, resolver)
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+
+		// Tokens named by --revoke are checked even when they are not registered
+		checked := make(map[common.Address]tokens.Token)
+		var order []common.Address
+		for _, token := range registered.Tokens() {
+			if !token.IsNative() {
+				checked[token.Address] = token
+				order = append(order, token.Address)
+			}
+		}
+		for _, token := range sortedTokens(revoke) {
+			if _, ok := checked[token.Address]; !ok {
+				checked[token.Address] = token
+				order = append(order, token.Address)
+			}
+		}
+
+		var opts *bind.TransactOpts
+		if len(revoke) > 0 || revokeEverything() {
+			if opts, err = cli.Transactor(cmd, client); err != nil {
+				log.Fatalf("Error: %v", err)
+			}
+			if opts.From != owner {
+				log.Fatalf("The signer holds %s, not --wallet %s", opts.From.Hex(), owner.Hex())
+			}
+		}
+
+		var infos []allowanceInfo
+		for _, address := range order {
+			token := checked[address]
+			allowance, err := approval.Allowance(ctx, client, address, owner, spender)
+			if err != nil {
+				log.Fatalf("Error: %v", err)
+			}
+			if allowance.Sign() == 0 {
+				continue
+			}
+
+			info := allowanceInfo{Symbol: token.Symbol, Token: address.Hex(), Spender: spender.Hex()}
+			if info.Allowance, err = formatAllowance(ctx, resolver, token, allowance); err != nil {
+				log.Fatalf("Error: %v", err)
+			}
+
+			if _, ok := revoke[address]; ok || revokeEverything() {
+				tx, err := approval.Revoke(ctx, client, opts, address, spender)
+				if err != nil {
+					log.Fatalf("Error revoking %s: %v", token.Symbol, err)
+				}
+				info.Revoked = tx.Hash().Hex()
+			}
+			infos = append(infos, info)
+		}
+
+		if approvalsFormat == "json" {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if infos == nil {
+				infos = []allowanceInfo{}
+			}
+			if err := encoder.Encode(infos); err != nil {
+				log.Fatalf("Error encoding allowances: %v", err)
+			}
+			return
+		}
+
+		fmt.Printf("🔓 %d non-zero allowance(s) of %s for %s across %d token(s)\n", len(infos), owner.Hex(), spender.Hex(), len(order))
+		for i, info := range infos {
+			fmt.Printf("\n%d. %s (%s)\n", i+1, info.Symbol, info.Token)
+			fmt.Println("   💰 Allowance:", info.Allowance)
+			if info.Revoked != "" {
+				fmt.Println("   🧹 Revoked in:", info.Revoked)
+			}
+		}
+	},
+}
+
+func init() {
+	ApprovalsCmd.Flags().StringVarP(&approvalsRPCURL, "rpc-url", "r", "https://eth-goerli.g.alchemy.com/v2/demo", "Ethereum RPC URL")
+	ApprovalsCmd.Flags().StringVarP(&approvalsWallet, "wallet", "w", "", "Wallet address whose allowances are listed")
+	ApprovalsCmd.Flags().StringVar(&approvalsSpender, "spender", constants.UniV2Router, "Spender the allowances are given to")
+	ApprovalsCmd.Flags().StringSliceVar(&approvalsRevoke, "revoke", nil, "Token symbol or address to revoke the allowance of (repeatable), or 'all'")
+	ApprovalsCmd.Flags().StringVar(&approvalsFormat, "format", "text", "Output format: text or json")
+
+	// Signing flags, only used by --revoke
+	ApprovalsCmd.Flags().StringP("keystore-file", "k", "", "Path to keystore file")
+	ApprovalsCmd.Flags().String("keystore-dir", "", "Keystore directory (default $TRADEBOT_KEYSTORE_DIR, then keystore_dir from the config file, then ./keystore)")
+	ApprovalsCmd.Flags().String("signer-url", "", "External signer URL speaking Clef's account_* API (default $TRADEBOT_SIGNER_URL, then signer_url from the config file)")
+	ApprovalsCmd.Flags().String("password-file", "", "File containing the keystore password")
+}
+
+// revokeEverything reports whether --revoke all was given
+func revokeEverything() bool {
+	for _, value := range approvalsRevoke {
+		if strings.EqualFold(value, revokeAll) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// revokedTokens resolves the tokens named by --revoke, leaving out "all"
+func revokedTokens(ctx context.Context, resolver *tokens.Resolver) (map[common.Address]tokens.Token, error) {
+	revoke := make(map[common.Address]tokens.Token, len(approvalsRevoke))
+	for _, value := range approvalsRevoke {
+		if strings.EqualFold(value, revokeAll) {
+			continue
+		}
+
+		token, err := resolver.Resolve(ctx, value)
+		if err != nil {
+			return nil, err
+		}
+		if token.IsNative() {
+			return nil, fmt.Errorf("%s is the native currency and has no allowance", token.Symbol)
+		}
+		revoke[token.Address] = token
+	}
+
+	return revoke, nil
+}
+
+// sortedTokens returns the tokens of a set ordered by address
+func sortedTokens(set map[common.Address]tokens.Token) []tokens.Token {
+	sorted := make([]tokens.Token, 0, len(set))
+	for _, token := range set {
+		sorted = append(sorted, token)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i].Address[:], sorted[j].Address[:]) < 0
+	})
+
+	return sorted
+}
+
+// formatAllowance prints an allowance in whole tokens, or "unlimited" for the
+// maximum uint256 that unlimited approvals set
+func formatAllowance(ctx context.Context, resolver *tokens.Resolver, token tokens.Token, allowance *big.Int) (string, error) {
+	if allowance.Cmp(math.MaxBig256) == 0 {
+		return "unlimited", nil
+	}
+
+	if token.Decimals == 0 {
+		resolved, err := resolver.Token(ctx, token.Address)
+		if err != nil {
+			return "", err
+		}
+		token = resolved
+	}
+
+	return token.Format(allowance), nil
+}
@@ -0,0 +1,14 @@
+package tokens
+
+import "github.com/spf13/cobra"
+
+// TokensCmd is the parent command for the token registry and token allowances
+var TokensCmd = &cobra.Command{
+	Use:   "tokens",
+	Short: "Inspect tokens and their router allowances",
+	Long:  `The tokens the trading commands know are the built-in defaults merged with an optional registry file given by --tokens-file, the TRADEBOT_TOKENS_FILE environment variable or tokens_file in the config file, plus the tokens of the registered pools.`,
+}
+
+func init() {
+	TokensCmd.AddCommand(ApprovalsCmd)
+}
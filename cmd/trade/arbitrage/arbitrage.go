@@ -5,9 +5,19 @@
 package arbitrage
 
 import (
+	"context"
 	"fmt"
-    "github.com/AnnaGD/go-eth-trade-bot/cmd/constants"
-    "github.com/spf13/cobra"
+
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/constants"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/approval"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/contracts/multicall3"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/poolreader"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/registry"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/swap"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/tokens"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/spf13/cobra"
 )
 
 // Creates the main arbitrage command - parent command that organizes related subcommands
@@ -17,12 +27,13 @@ var ArbitrageCmd = &cobra.Command{
 	Long: `The arbitrage command allows you to scan for and execute profitable arbitrage opportunities between different Uniswap V2 pools. It can detect imbalances and automatically execute trades to capitalize on price differences.`,
 }
 
-// Connects three subcommands `ScanCmd`, `ExecuteCmd`, and `AutoCmd` to the main `ArbitrageCmd`
+// Connects the subcommands `ScanCmd`, `ExecuteCmd`, `AutoCmd` and `CyclesCmd` to the main `ArbitrageCmd`
 func init() {
 
 	ArbitrageCmd.AddCommand(ScanCmd)
 	ArbitrageCmd.AddCommand(ExecuteCmd)
 	ArbitrageCmd.AddCommand(AutoCmd)
+	ArbitrageCmd.AddCommand(CyclesCmd)
 
 
 	// Persistent flags for all arbitrage subcommands
@@ -36,6 +47,15 @@ func init() {
 	// Path to the user's keystore file for authentication
 	ArbitrageCmd.PersistentFlags().StringP("keystore-file", "k", "", "Path to keystore file")
 
+	// Keystore directory searched for --wallet when no --keystore-file is given
+	ArbitrageCmd.PersistentFlags().String("keystore-dir", "", "Keystore directory (default $TRADEBOT_KEYSTORE_DIR, then keystore_dir from the config file, then ./keystore)")
+
+	// External Clef-compatible signer used instead of a keystore
+	ArbitrageCmd.PersistentFlags().String("signer-url", "", "External signer URL speaking Clef's account_* API (default $TRADEBOT_SIGNER_URL, then signer_url from the config file)")
+
+	// File holding the keystore password (otherwise TRADEBOT_KEYSTORE_PASSWORD, then a prompt)
+	ArbitrageCmd.PersistentFlags().String("password-file", "", "File containing the keystore password")
+
 	// Minimum profit percentage threshold (default 0.5%)
 	ArbitrageCmd.PersistentFlags().Float64P("min-profit", "p", 0.5, "Minimum profit percentage")
 
@@ -45,15 +65,50 @@ func init() {
 	// Gas limit for transactions (default 350000)
 	ArbitrageCmd.PersistentFlags().Uint64("gas-limit", 350000, "Gas limit for transactions")
 
-	// Display available pools
-	fmt.Println("Available pools for arbitrage: ")
-	for poolName, address := range constants.UniV2Pools {
-		targetRatio, exists := constants.TargetRatios[poolName]
-		if exists {
-			fmt.Printf("  %s (%s) - Target Ratio: %.2f\n", poolName, address[:10]+"...", targetRatio)
-		} else {
-			fmt.Printf("  %s (%s)\n", poolName, address[:10]+"...")
-		}
+	// Read pool state from the deterministic in-memory fake instead of the RPC endpoint
+	ArbitrageCmd.PersistentFlags().Bool("fake-pools", false, "Use deterministic simulated pool state instead of the RPC endpoint")
+
+	// Multicall3 contract used to batch pool reads into one RPC round trip
+	ArbitrageCmd.PersistentFlags().String("multicall", multicall3.DefaultAddress.Hex(), "Multicall3 contract address for batched pool reads")
+
+	// Uniswap V2 router that executed swaps are sent through
+	ArbitrageCmd.PersistentFlags().String("router", constants.UniV2Router, "Uniswap V2 router address for executed swaps")
+
+	// How much of the input token the router is approved for before a live swap
+	ArbitrageCmd.PersistentFlags().String("approval", string(approval.Exact), "Router allowance to approve when too low: exact, unlimited or none")
+}
+
+// checkApproval warns when the router may not pull the quote's input from the
+// account, in which case a dry run reverts until the allowance is approved
+func checkApproval(ctx context.Context, client *ethclient.Client, from, router common.Address, quote *swap.Quote) error {
+	tokenIn := quote.Route.Hops[0].From
+	allowance, err := approval.Allowance(ctx, client, tokenIn.Address, from, router)
+	if err != nil {
+		return err
+	}
+	if allowance.Cmp(quote.AmountIn()) < 0 {
+		fmt.Printf("⚠️ Router allowance is %s, below the %s %s swapped: live execution approves it first\n",
+			tokens.FormatUnits(allowance, tokenIn.Decimals), tokens.FormatUnits(quote.AmountIn(), tokenIn.Decimals), tokenIn.Symbol)
+	}
+
+	return nil
+}
+
+// openPoolReader returns the pool reader selected by the --rpc-url, --fake-pools and --multicall
+// flags. The fake reader is seeded with the registered pools.
+func openPoolReader(cmd *cobra.Command, pools *registry.Registry) (poolreader.PoolReader, error) {
+	rpcURL, _ := cmd.Flags().GetString("rpc-url")
+	fake, _ := cmd.Flags().GetBool("fake-pools")
+	multicall, _ := cmd.Flags().GetString("multicall")
+
+	if !common.IsHexAddress(multicall) {
+		return nil, fmt.Errorf("invalid multicall address %q", multicall)
 	}
 
+	return poolreader.Open(poolreader.Options{
+		RPCURL:    rpcURL,
+		Fake:      fake,
+		Pools:     pools,
+		Multicall: common.HexToAddress(multicall),
+	})
 }
@@ -3,22 +3,32 @@
 
 	Simulation vs. Production:
 
-	This is clearly a demonstration version with simulated trade finding and execution
-	Uses a simplified pattern (scanCount%4 == 0) to simulate finding opportunities
-	Simulates execution with time.Sleep(2 * time.Second)
-	Would need to be replaced with actual pool checking and trade execution in production
+	Opportunities are found with the same checkPool logic and pool reader as the scan command
+	Each trade is run through eth_call first, from --wallet, and only sent with --dry-run=false,
+	signed by the --keystore-file account unlocked once at startup
+	With --fake-pools there is no chain, so trades are only quoted
 */
 
-
 package arbitrage
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/approval"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/cli"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/poolreader"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/registry"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/swap"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/tokens"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/spf13/cobra"
 )
 
@@ -27,13 +37,16 @@ var (
 	maxExecutions int
 	autoTimeLimit uint
 	minProfitAuto float64
+	autoWatchSync bool
+	autoDryRun    bool
+	autoSlippage  float64
 )
 
 // The auto command for arbitrage
 var AutoCmd = &cobra.Command{
 	Use:   "auto",
 	Short: "Automatically scan and execute arbitrage trades",
-	Long: `Run the arbitrage bot in automatic mode, continuously scanning for opportunities and executing trades when profitable opportunities are found. Set minimum profit thresholds and other safety parameters to control execution.`,
+	Long:  `Run the arbitrage bot in automatic mode, continuously scanning for opportunities and executing trades when profitable opportunities are found. Set minimum profit thresholds and other safety parameters to control execution.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		// Get persistent flags
 		rpcURL, _ := cmd.Flags().GetString("rpc-url")
@@ -56,6 +69,11 @@ var AutoCmd = &cobra.Command{
 		fmt.Printf("  Min Profit: %.2f%%\n", minProfit)
 		fmt.Printf("  Gas Price: %s\n", gasPrice)
 		fmt.Printf("  Gas Limit: %d\n", gasLimit)
+		if autoDryRun {
+			fmt.Println("  Mode: DRY RUN (no transaction will be sent)")
+		} else {
+			fmt.Println("  Mode: LIVE EXECUTION")
+		}
 
 		if maxExecutions > 0 {
 			fmt.Printf("  Max Executions: %d\n", maxExecutions)
@@ -69,6 +87,41 @@ var AutoCmd = &cobra.Command{
 			fmt.Println("  Time Limit: None (running until stopped)")
 		}
 
+		// Every registered pool is watched
+		registered, err := cli.OpenRegistry(cmd)
+		if err != nil {
+			fmt.Printf("Error loading pool registry: %v\n", err)
+			return
+		}
+		pools := registered.Pools()
+
+		// Connect to the pool state source (RPC or the deterministic fake)
+		reader, err := openPoolReader(cmd, registered)
+		if err != nil {
+			fmt.Printf("Error connecting to Ethereum: %v\n", err)
+			return
+		}
+		ctx := cmd.Context()
+
+		// Ratios are compared in whole tokens, which needs the decimals of every pool token
+		resolver, err := cli.OpenTokens(cmd, registered, reader)
+		if err != nil {
+			fmt.Printf("Error loading token registry: %v\n", err)
+			return
+		}
+		if err := completePools(ctx, resolver, pools); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+
+		// Set up the router and, for live execution, the signing account
+		trader, err := newAutoTrader(cmd)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		defer trader.close()
+
 		fmt.Println("\n⚠️ Press Ctrl+C to stop the bot")
 		fmt.Println("\n🔄 Bot started at", time.Now().Format(time.RFC3339))
 
@@ -76,9 +129,25 @@ var AutoCmd = &cobra.Command{
 		sigs := make(chan os.Signal, 1)
 		signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
 
-		// Create a ticker for the scan inerval
-		ticker := time.NewTicker(time.Duration(autoInterval) * time.Second)
-		defer ticker.Stop()
+		// Either poll every interval or re-evaluate on Sync events
+		var ticks <-chan time.Time
+		var events <-chan *poolreader.Batch
+		var watchErrs <-chan error
+		if autoWatchSync {
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+
+			events, watchErrs, err = watchPools(ctx, reader, pools)
+			if err != nil {
+				fmt.Printf("Error watching pools: %v\n", err)
+				return
+			}
+		} else {
+			// Create a ticker for the scan inerval
+			ticker := time.NewTicker(time.Duration(autoInterval) * time.Second)
+			defer ticker.Stop()
+			ticks = ticker.C
+		}
 
 		// Create a timeout if specified
 		var timeout <-chan time.Time
@@ -90,38 +159,102 @@ var AutoCmd = &cobra.Command{
 		executionCount := 0
 		scanCount := 0
 
+		// act looks for the most profitable pool among pools in the batch and executes it.
+		// It reports whether the bot should stop.
+		act := func(batch *poolreader.Batch, pools []*registry.Pool) bool {
+
+			// Find the most profitable pool from the shared reader's view of the chain
+			var best *poolCheck
+			for _, pool := range pools {
+				check, err := checkPool(batch, pool)
+				if errors.Is(err, errNoTarget) {
+					continue
+				}
+				if err != nil {
+					fmt.Printf("Error reading %s: %v\n", pool.Name, err)
+					continue
+				}
+				if check.Significant() && check.Trade != nil && (best == nil || check.ProfitPercent > best.ProfitPercent) {
+					best = check
+				}
+			}
+
+			if best == nil {
+				fmt.Println("No profitable opportunities found in this scan")
+				return false
+			}
+
+			profit := best.ProfitPercent
+			fmt.Printf("✅ Opportunity found in %s! Potential profit: %.2f%%\n", best.Pool.Name, profit)
+			fmt.Printf("📐 Trade: %s\n", describeTrade(best.Trade, best.Pool))
+
+			if profit < minProfit {
+				fmt.Printf("⚠️ Profit too low (%.2f%% < %.2f%%). Skipping execution.\n",
+					profit, minProfit)
+				return false
+			}
+
+			executionCount++
+			fmt.Printf("💰 Executing arbitrage trade #%d\n", executionCount)
+			if err := autoExecute(ctx, trader, reader, best); err != nil {
+				fmt.Printf("❌ Trade #%d failed: %v\n", executionCount, err)
+			}
+
+			if maxExecutions > 0 && executionCount >= maxExecutions {
+				fmt.Printf("\n🛑 Reached maximum number of executions (%d)\n", maxExecutions)
+				return true
+			}
+
+			return false
+		}
+
+		// In watch mode, start from a full scan so pools that never move are still evaluated once
+		if autoWatchSync {
+			scanCount++
+			batch, err := readPools(ctx, reader, pools)
+			if err != nil {
+				fmt.Printf("Error reading pools: %v\n", err)
+				return
+			}
+			fmt.Printf("\nInitial pool state at block %d\n", batch.BlockNumber)
+			if act(batch, pools) {
+				return
+			}
+		}
+
 		for {
 			select {
-			case <-ticker.C:
+			case <-ticks:
 				scanCount++
 				fmt.Printf("\n[%s] Scan #%d: Checking for arbitrage opportunities...\n",
 					time.Now().Format("15:04:05"), scanCount)
 
-				// Simulate finding an opportunity (in reality, this would check pool states)
-				profitFound := scanCount%4 == 0 // Just a quick demo
-
-				if profitFound {
-					profit := minProfit + float64(scanCount%5)
-					fmt.Printf("✅ Opportunity found! Potential profit: %.2f%%\n", profit)
-
-					if profit >= minProfit {
-						executionCount++
-						fmt.Printf("💰 Executing arbitrage trade #%d\n", executionCount)
-						time.Sleep(2 * time.Second)                                         // Simulate execution time
-						fmt.Printf("✅ Trade executed! Actual profit: %.2f%%\n", profit*0.9) // Slightly less due to slippage
-
-						if maxExecutions > 0 && executionCount >= maxExecutions {
-							fmt.Printf("\n🛑 Reached maximum number of executions (%d)\n", maxExecutions)
-							return
-						}
-					} else {
-						fmt.Printf("⚠️ Profit too low (%.2f%% < %.2f%%). Skipping execution.\n",
-							profit, minProfit)
-					}
-				} else {
-					fmt.Println("No profitable opportunities found in this scan")
+				// Read every pool in one round trip, pinned to a single block
+				batch, err := readPools(ctx, reader, pools)
+				if err != nil {
+					fmt.Printf("Error reading pools: %v\n", err)
+					continue
 				}
 
+				if act(batch, pools) {
+					return
+				}
+
+			case batch := <-events:
+				// Only the pools whose reserves moved are re-evaluated
+				scanCount++
+				fmt.Printf("\n[%s] Block %d: %d pools moved\n",
+					time.Now().Format("15:04:05"), batch.BlockNumber, len(batch.Reserves))
+
+				if act(batch, movedPools(batch, pools)) {
+					return
+				}
+
+			case err := <-watchErrs:
+				fmt.Printf("\nError watching pools: %v\n", err)
+				fmt.Printf("Summary: %d scans, %d executions\n", scanCount, executionCount)
+				return
+
 			case <-timeout:
 				if autoTimeLimit > 0 {
 					fmt.Printf("\n⏱️ Auto mode time limit (%d minutes) reached\n", autoTimeLimit)
@@ -150,9 +283,169 @@ func init() {
 	// Duration to run in automatic mode
 	AutoCmd.Flags().UintVar(&autoTimeLimit, "time-limit", 0, "Time limit in minutes (0 for no limit)")
 
+	// Re-evaluate pools on Uniswap V2 Sync events instead of polling on a fixed interval
+	AutoCmd.Flags().BoolVar(&autoWatchSync, "watch", false, "Re-evaluate pools on Sync events instead of polling every interval")
+
+	// Simulation toggle: trades are only sent with --dry-run=false
+	AutoCmd.Flags().BoolVar(&autoDryRun, "dry-run", true, "Simulate trades through eth_call without sending transactions")
+
+	// Maximum acceptable price slippage percentage for executed trades
+	AutoCmd.Flags().Float64Var(&autoSlippage, "slippage", 0.5, "Maximum slippage percentage")
+
 	// Override for minimum profit specifically in auto mode
 	AutoCmd.Flags().Float64Var(&minProfitAuto, "auto-min-profit", 0, "Minimum profit percentage override for auto mode")
 
 	// Add this command to the parent arbitrage command
 	ArbitrageCmd.AddCommand(AutoCmd)
 }
+
+// autoTrader is what auto mode executes trades with
+type autoTrader struct {
+	client   *ethclient.Client // nil when pools are simulated
+	executor *swap.Executor
+	from     common.Address     // account trades are simulated or sent from
+	opts     *bind.TransactOpts // signing options, live execution only
+	slippage uint64
+	deadline time.Duration
+	approval approval.Mode // how the router allowance is raised, live execution only
+}
+
+// newAutoTrader binds the router and, for live execution, unlocks the signer
+// up front so the bot never stops to ask for a password mid-run
+func newAutoTrader(cmd *cobra.Command) (*autoTrader, error) {
+	rpcURL, _ := cmd.Flags().GetString("rpc-url")
+	wallet, _ := cmd.Flags().GetString("wallet")
+	fake, _ := cmd.Flags().GetBool("fake-pools")
+	router, _ := cmd.Flags().GetString("router")
+
+	trader := &autoTrader{deadline: 5 * time.Minute}
+
+	var err error
+	if trader.slippage, err = swap.SlippageBps(autoSlippage); err != nil {
+		return nil, err
+	}
+	if trader.approval, err = cli.ApprovalMode(cmd); err != nil {
+		return nil, err
+	}
+	if !common.IsHexAddress(router) {
+		return nil, fmt.Errorf("invalid router address %q", router)
+	}
+
+	if fake {
+		if !autoDryRun {
+			return nil, errors.New("--fake-pools cannot be used for live execution")
+		}
+		return trader, nil
+	}
+
+	if trader.client, err = ethclient.DialContext(cmd.Context(), rpcURL); err != nil {
+		return nil, fmt.Errorf("connecting to Ethereum: %w", err)
+	}
+	if trader.executor, err = swap.NewExecutor(trader.client, common.HexToAddress(router)); err != nil {
+		trader.close()
+		return nil, fmt.Errorf("binding router: %w", err)
+	}
+
+	if autoDryRun {
+		if common.IsHexAddress(wallet) {
+			trader.from = common.HexToAddress(wallet)
+		}
+		return trader, nil
+	}
+
+	if trader.opts, err = cli.Transactor(cmd, trader.client); err != nil {
+		trader.close()
+		return nil, err
+	}
+	trader.from = trader.opts.From
+	fmt.Printf("  Signing as: %s\n", trader.from.Hex())
+
+	return trader, nil
+}
+
+func (t *autoTrader) close() {
+	if t.client != nil {
+		t.client.Close()
+	}
+}
+
+// autoExecute quotes the rebalancing trade of a pool, runs it through eth_call
+// and, outside dry-run, sends it
+func autoExecute(ctx context.Context, trader *autoTrader, reader poolreader.PoolReader, check *poolCheck) error {
+	route, err := poolRoute(ctx, reader, check)
+	if err != nil {
+		return err
+	}
+
+	quote, err := swap.QuoteRoute(ctx, reader, route, check.Trade.AmountIn, trader.slippage)
+	if err != nil {
+		return fmt.Errorf("quoting trade: %w", err)
+	}
+	printQuote(quote)
+
+	if trader.executor == nil {
+		fmt.Println("⚠️ Pools are simulated: trade not sent")
+		return nil
+	}
+	if trader.from == (common.Address{}) {
+		fmt.Println("⚠️ No --wallet to simulate from: trade not simulated")
+		return nil
+	}
+
+	deadline := time.Now().Add(trader.deadline)
+	simulation, err := trader.executor.Simulate(ctx, trader.from, quote, trader.from, deadline, nil)
+	if err != nil {
+		return err
+	}
+	if simulation.Reverted {
+		return fmt.Errorf("router reverted: %s", revertMessage(simulation.Revert))
+	}
+	tokenOut := route.Hops[0].To
+	fmt.Printf("🔍 Simulated: %s %s out, %d gas\n", tokens.FormatUnits(simulation.AmountOut(), tokenOut.Decimals), tokenOut.Symbol, simulation.GasUsed)
+
+	if autoDryRun {
+		return nil
+	}
+
+	// The router is only approved once the simulated trade went through
+	if err := cli.EnsureApproval(ctx, trader.client, trader.opts, trader.executor.Router(), quote, trader.approval); err != nil {
+		return err
+	}
+
+	result, err := trader.executor.Swap(ctx, trader.opts, quote, trader.from, deadline)
+	if result != nil && result.Transaction != nil {
+		fmt.Printf("📤 Transaction: %s\n", result.Transaction.Hash().Hex())
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✅ Trade executed in block %d! Received %s %s (expected %s)\n",
+		result.Receipt.BlockNumber, tokens.FormatUnits(result.AmountOut(), tokenOut.Decimals), tokenOut.Symbol,
+		tokens.FormatUnits(quote.AmountOut(), tokenOut.Decimals))
+
+	return nil
+}
+
+// poolRoute is the single-hop route that performs a pool's rebalancing trade
+func poolRoute(ctx context.Context, reader poolreader.PoolReader, check *poolCheck) (*swap.Route, error) {
+	pool := check.Pool
+
+	token0, token1, err := reader.Tokens(ctx, pool.Address)
+	if err != nil {
+		return nil, fmt.Errorf("reading tokens of %s: %w", pool.Name, err)
+	}
+
+	hop := swap.Hop{
+		PoolName:   pool.Name,
+		Pool:       pool.Address,
+		From:       token1,
+		To:         token0,
+		FeeBps:     pool.FeeBps,
+		ZeroForOne: check.Trade.ZeroForOne,
+	}
+	if hop.ZeroForOne {
+		hop.From, hop.To = token0, token1
+	}
+
+	return &swap.Route{Hops: []swap.Hop{hop}}, nil
+}
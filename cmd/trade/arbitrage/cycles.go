@@ -0,0 +1,135 @@
+/*
+	The cycles.go file implements the "cycles" subcommand. It builds a token graph from every pool in
+	the pool registry, looks for negative cycles in -log(rate) space and lists the profitable
+	triangular and multi-hop routes it finds, each sized for maximum profit after fees. The token
+	sequence of a cycle can be passed straight to `arbitrage execute --path`.
+*/
+
+package arbitrage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/cli"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/cycles"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/poolreader"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/registry"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/tokens"
+	"github.com/spf13/cobra"
+)
+
+var (
+	maxHops     int
+	cyclesLimit int
+)
+
+var CyclesCmd = &cobra.Command{
+	Use:   "cycles",
+	Short: "Find profitable multi-hop arbitrage cycles",
+	Long:  `Build a token graph from all known pools and enumerate the cycles (such as eUSD → eEUR → eGBP → eUSD) that return more than they start with after fees, along with the optimal input size and expected profit of each.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		minProfit, _ := cmd.Flags().GetFloat64("min-profit")
+
+		fmt.Println("🔺 Searching for arbitrage cycles...")
+		fmt.Printf("  Max Hops: %d\n", maxHops)
+		fmt.Printf("  Min Profit: %.2f%%\n", minProfit)
+
+		registered, err := cli.OpenRegistry(cmd)
+		if err != nil {
+			fmt.Printf("Error loading pool registry: %v\n", err)
+			return
+		}
+
+		// Connect to the pool state source (RPC or the deterministic fake)
+		reader, err := openPoolReader(cmd, registered)
+		if err != nil {
+			fmt.Printf("Error connecting to Ethereum: %v\n", err)
+			return
+		}
+
+		graph, blockNumber, err := buildGraph(cmd.Context(), reader, registered)
+		if err != nil {
+			fmt.Printf("Error building token graph: %v\n", err)
+			return
+		}
+		fmt.Printf("  Pool state at block %d\n", blockNumber)
+
+		found := 0
+		for _, cycle := range graph.Profitable(maxHops) {
+			if cycle.ProfitPercent < minProfit {
+				continue
+			}
+
+			found++
+			fmt.Printf("\n💰 Cycle #%d: %s (%.2f%%)\n", found, cycleRoute(cycle), cycle.ProfitPercent)
+			for i, edge := range cycle.Edges {
+				fmt.Printf("  Hop %d: %s %s → %s %s via %s\n", i+1,
+					tokens.FormatUnits(cycle.Amounts[i], edge.From.Decimals), edge.From.Symbol,
+					tokens.FormatUnits(cycle.Amounts[i+1], edge.To.Decimals), edge.To.Symbol, edge.PoolName)
+			}
+			start := cycle.Start()
+			fmt.Printf("  Profit: %s %s\n", tokens.FormatUnits(cycle.Profit, start.Decimals), start.Symbol)
+			fmt.Printf("  Execute with: --path %s\n", strings.Join(cycleTokens(cycle), ","))
+
+			if cyclesLimit > 0 && found >= cyclesLimit {
+				break
+			}
+		}
+
+		if found == 0 {
+			fmt.Println("\nNo profitable cycles found")
+		}
+	},
+}
+
+// buildGraph reads every registered pool in one batch and adds it to a token graph
+func buildGraph(ctx context.Context, reader poolreader.PoolReader, registered *registry.Registry) (*cycles.Graph, uint64, error) {
+	pools := registered.Pools()
+
+	batch, err := readPools(ctx, reader, pools)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	graph := cycles.NewGraph()
+	for _, pool := range pools {
+		reserves, ok := batch.Reserves[pool.Address]
+		if !ok {
+			continue
+		}
+
+		token0, token1, err := reader.Tokens(ctx, pool.Address)
+		if err != nil {
+			return nil, 0, fmt.Errorf("reading tokens of %s: %w", pool.Name, err)
+		}
+
+		graph.AddPool(pool.Name, reserves, token0, token1, pool.FeeBps)
+	}
+
+	return graph, batch.BlockNumber, nil
+}
+
+// cycleTokens returns the token symbols along a cycle, ending back at the start
+func cycleTokens(cycle *cycles.Cycle) []string {
+	symbols := []string{cycle.Start().Symbol}
+	for _, edge := range cycle.Edges {
+		symbols = append(symbols, edge.To.Symbol)
+	}
+
+	return symbols
+}
+
+// cycleRoute formats a cycle as "A → B → C → A"
+func cycleRoute(cycle *cycles.Cycle) string {
+	return strings.Join(cycleTokens(cycle), " → ")
+}
+
+func init() {
+	// Longest cycle to consider, in pools traversed (3 = triangular)
+	CyclesCmd.Flags().IntVar(&maxHops, "max-hops", 3, "Maximum number of hops in a cycle")
+
+	// Cap on the number of cycles printed (0 for all)
+	CyclesCmd.Flags().IntVar(&cyclesLimit, "limit", 10, "Maximum number of cycles to print (0 for all)")
+}
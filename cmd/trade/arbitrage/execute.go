@@ -6,9 +6,22 @@
 package arbitrage
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"math/big"
+	"strings"
 	"time"
 
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/constants"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/approval"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/cli"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/poolreader"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/swap"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/tokens"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/spf13/cobra"
 )
 
@@ -17,12 +30,14 @@ var (
 	maxSlippage       float64
 	executionDeadline uint
 	dryRun            bool
+	executeAmount     string
+	simulateBlock     uint64
 )
 
 var ExecuteCmd = &cobra.Command{
 	Use:   "execute",
 	Short: "Execute an arbitrage trade",
-	Long: `Execute an arbitrage trade across multiple Uniswap V2 pools.This command allows you to specify a token path to exploit price differences between pools for profit.`,
+	Long:  `Execute an arbitrage trade across multiple Uniswap V2 pools.This command allows you to specify a token path to exploit price differences between pools for profit.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		// Get persistant flags
 		rpcURL, _ := cmd.Flags().GetString("rpc-url")
@@ -53,39 +68,388 @@ var ExecuteCmd = &cobra.Command{
 		deadline := time.Now().Add(time.Duration(executionDeadline) * time.Minute)
 		fmt.Printf("  Deadline: %s\n", deadline.Format(time.RFC3339))
 
-		// Simulate arbitrage execution
+		ctx := cmd.Context()
+		plan, err := planExecution(cmd)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		defer plan.close()
+
+		fmt.Println("\n🧮 Quoting arbitrage path...")
+		if plan.blockNumber != nil {
+			fmt.Printf("  Pool state at block %s\n", plan.blockNumber)
+		}
+		printQuote(plan.quote)
+
+		if plan.executor == nil {
+			// Simulated pools have no chain to run the transaction against
+			fmt.Println("\n⚠️ Pools are simulated: the router call was not run. Drop --fake-pools to simulate against the chain.")
+			return
+		}
+
+		// A live trade below the minimum profit is dropped before anything runs on-chain
+		if !dryRun && plan.quote.Route.IsCycle() {
+			profitPercent := percentGain(plan.quote.AmountIn(), plan.quote.AmountOutMin)
+			if profitPercent < minProfit {
+				fmt.Printf("\n⏭️ Profit after slippage is %.2f%%, below the %.2f%% minimum. Not executing.\n", profitPercent, minProfit)
+				return
+			}
+		}
+
 		if dryRun {
-			// Simulate calculation without actual execution
-			fmt.Println("\n🧮 Calculating arbitrage path...")
-			time.Sleep(2 * time.Second)
-			fmt.Println("  Checking pool states...")
-			time.Sleep(1 * time.Second)
-			fmt.Println("  Calculating optimal amounts...")
-			time.Sleep(1 * time.Second)
-
-			fmt.Println("\n📊 Arbitrage simulation results:")
-			fmt.Println("  Initial: 1 ETH")
-			fmt.Println("  Step 1: ETH → USDC = 1800 USDC")
-			fmt.Println("  Step 2: USDC → DAI = 1810 DAI")
-			fmt.Println("  Step 3: DAI → ETH = 1.01 ETH")
-			fmt.Println("  Final: 1.01 ETH")
-			fmt.Println("  Profit: 0.01 ETH (1.00%)")
-			fmt.Println("  Estimated Gas Cost: 0.005 ETH")
-			fmt.Println("  Net Profit: 0.005 ETH (0.50%)")
+			if err := checkApproval(ctx, plan.client, plan.from, plan.executor.Router(), plan.quote); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+		}
+
+		// Dry-run and live execution both run the exact transaction through eth_call first,
+		// after a simulated approval when the router is not approved yet
+		fmt.Println("\n🔍 Simulating the swap transaction...")
+		simulation, err := plan.executor.Simulate(ctx, plan.from, plan.quote, plan.from, deadline, plan.blockNumber)
+		if err != nil {
+			fmt.Printf("Error simulating swap: %v\n", err)
+			return
+		}
+		if simulation.Reverted {
+			fmt.Printf("❌ Router reverted: %s\n", revertMessage(simulation.Revert))
+			return
+		}
+		if err := reportSimulation(cmd, plan, simulation); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
 
+		if dryRun {
 			fmt.Println("\n✅ Simulation complete. Use --dry-run=false to execute this trade.")
-		} else {
-			// TODO: Implement actual arbitrage execution
-			fmt.Println("\n⚠️ Arbitrage execution not yet implemented")
+			return
+		}
+
+		// Approve the router only once the trade is known to go through
+		fmt.Println("\n🔓 Checking router allowance...")
+		if err := cli.EnsureApproval(ctx, plan.client, plan.opts, plan.executor.Router(), plan.quote, plan.approval); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
 		}
+
+		executeSwap(cmd, plan, deadline)
 	},
 }
 
+// execution is everything dry-run and live execution share: the quoted route,
+// the router it goes through and the account it is sent from
+type execution struct {
+	client      *ethclient.Client
+	quote       *swap.Quote
+	executor    *swap.Executor // nil when pools are simulated
+	from        common.Address
+	opts        *bind.TransactOpts // signing options, live execution only
+	blockNumber *big.Int           // pinned block, nil for the latest
+	approval    approval.Mode      // how the router allowance is raised, live execution only
+}
+
+func (e *execution) close() {
+	if e.client != nil {
+		e.client.Close()
+	}
+}
+
+// planExecution resolves --path, quotes it from pool state and, when
+// running against a chain, binds the router and the sending account
+func planExecution(cmd *cobra.Command) (*execution, error) {
+	ctx := cmd.Context()
+	rpcURL, _ := cmd.Flags().GetString("rpc-url")
+	wallet, _ := cmd.Flags().GetString("wallet")
+	fake, _ := cmd.Flags().GetBool("fake-pools")
+	router, _ := cmd.Flags().GetString("router")
+
+	slippage, err := swap.SlippageBps(maxSlippage)
+	if err != nil {
+		return nil, err
+	}
+	approvalMode, err := cli.ApprovalMode(cmd)
+	if err != nil {
+		return nil, err
+	}
+	if !common.IsHexAddress(router) {
+		return nil, fmt.Errorf("invalid router address %q", router)
+	}
+	if simulateBlock != 0 && !dryRun {
+		return nil, errors.New("--block only applies to --dry-run")
+	}
+	if fake && !dryRun {
+		return nil, errors.New("--fake-pools cannot be used for live execution")
+	}
+
+	registered, err := cli.OpenRegistry(cmd)
+	if err != nil {
+		return nil, fmt.Errorf("loading pool registry: %w", err)
+	}
+
+	plan := &execution{approval: approvalMode}
+	var reader poolreader.PoolReader
+	if fake {
+		reader = poolreader.NewFake(registered)
+	} else {
+		if plan.client, err = ethclient.DialContext(ctx, rpcURL); err != nil {
+			return nil, fmt.Errorf("connecting to Ethereum: %w", err)
+		}
+		live := poolreader.NewLive(plan.client)
+		reader = live
+		if simulateBlock != 0 {
+			reader = live.At(simulateBlock)
+			plan.blockNumber = new(big.Int).SetUint64(simulateBlock)
+		}
+	}
+
+	if !fake {
+		if plan.executor, err = swap.NewExecutor(plan.client, common.HexToAddress(router)); err != nil {
+			plan.close()
+			return nil, fmt.Errorf("binding router: %w", err)
+		}
+	}
+
+	resolver, err := cli.OpenTokens(cmd, registered, reader)
+	if err != nil {
+		plan.close()
+		return nil, fmt.Errorf("loading token registry: %w", err)
+	}
+	path, err := plan.resolvePath(ctx, resolver)
+	if err != nil {
+		plan.close()
+		return nil, fmt.Errorf("resolving path: %w", err)
+	}
+	route, err := swap.ResolveRoute(ctx, reader, registered, path)
+	if err != nil {
+		plan.close()
+		return nil, fmt.Errorf("resolving path %s: %w", strings.Join(tokenPath, ","), err)
+	}
+
+	if !fake {
+		if err := plan.bindAccount(cmd, wallet); err != nil {
+			plan.close()
+			return nil, err
+		}
+	}
+
+	var amountIn *big.Int
+	if executeAmount != "" {
+		if amountIn, err = plan.amountIn(ctx, route); err != nil {
+			plan.close()
+			return nil, fmt.Errorf("invalid --amount: %w", err)
+		}
+	}
+
+	if plan.quote, err = swap.QuoteRoute(ctx, reader, route, amountIn, slippage); err != nil {
+		plan.close()
+		return nil, fmt.Errorf("quoting path: %w", err)
+	}
+
+	return plan, nil
+}
+
+// resolvePath resolves the --path symbols and addresses to token addresses.
+// The router only trades ether wrapped, so the native currency stands for its
+// WETH, or the built-in WETH when pools are simulated.
+func (e *execution) resolvePath(ctx context.Context, resolver *tokens.Resolver) ([]common.Address, error) {
+	weth := common.HexToAddress(constants.WETH)
+	if e.executor != nil {
+		var err error
+		if weth, err = e.executor.WETH(ctx); err != nil {
+			return nil, fmt.Errorf("reading router WETH: %w", err)
+		}
+	}
+
+	path := make([]common.Address, len(tokenPath))
+	for i, symbolOrAddress := range tokenPath {
+		token, err := resolver.Resolve(ctx, symbolOrAddress)
+		if err != nil {
+			return nil, err
+		}
+
+		path[i] = token.Address
+		if token.IsNative() {
+			path[i] = weth
+		}
+	}
+
+	return path, nil
+}
+
+// bindAccount sets the account the swap is sent from: --wallet for a dry run,
+// so balances and router allowances are real, or the unlocked signer
+func (e *execution) bindAccount(cmd *cobra.Command, wallet string) error {
+	if dryRun {
+		if !common.IsHexAddress(wallet) {
+			return errors.New("--wallet is required to simulate the swap from your account")
+		}
+		e.from = common.HexToAddress(wallet)
+		return nil
+	}
+
+	opts, err := cli.Transactor(cmd, e.client)
+	if err != nil {
+		return err
+	}
+	e.opts, e.from = opts, opts.From
+
+	return nil
+}
+
+// amountIn converts --amount into base units of the route's first token,
+// reading the sending account's balance for "max" and percentages, less the
+// gas of the swap when that balance is ether
+func (e *execution) amountIn(ctx context.Context, route *swap.Route) (*big.Int, error) {
+	amount, err := tokens.ParseAmount(executeAmount)
+	if err != nil {
+		return nil, err
+	}
+
+	first := route.Hops[0].From
+	if !amount.RelativeToBalance() {
+		return amount.BaseUnits(first.Decimals, nil)
+	}
+	if e.client == nil {
+		return nil, fmt.Errorf("%s needs a balance, which simulated pools do not have", amount)
+	}
+
+	// A dry run has no signer and reads the --wallet balance as it is
+	token := tokens.Token{Symbol: first.Symbol, Address: first.Address, Decimals: first.Decimals}
+	var balance *big.Int
+	if e.opts != nil {
+		balance, err = tokens.Spendable(ctx, e.client, token, e.opts)
+	} else {
+		balance, err = tokens.Balance(ctx, e.client, token, e.from)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return amount.BaseUnits(first.Decimals, balance)
+}
+
+// reportSimulation prints the router's per-hop amounts, the gas the swap would
+// use and, when the route starts and ends with WETH, the profit net of gas
+func reportSimulation(cmd *cobra.Command, plan *execution, simulation *swap.Simulation) error {
+	ctx := cmd.Context()
+	route := plan.quote.Route
+
+	fmt.Println("\n📊 Simulation results:")
+	if simulation.Approved {
+		fmt.Println("  Run after approving the router, which is not approved yet")
+	}
+	for i, hop := range route.Hops {
+		fmt.Printf("  Hop %d: %s → %s\n", i+1, formatAmount(simulation.Amounts[i], hop.From), formatAmount(simulation.Amounts[i+1], hop.To))
+	}
+
+	gasPrice, err := effectiveGasPrice(cmd, plan.client)
+	if err != nil {
+		return fmt.Errorf("reading gas price: %w", err)
+	}
+	gasCost := new(big.Int).Mul(new(big.Int).SetUint64(simulation.GasUsed), gasPrice)
+	fmt.Printf("  Gas Used: %d\n", simulation.GasUsed)
+	fmt.Printf("  Gas Cost: %s wei (at %s wei/gas)\n", gasCost, gasPrice)
+
+	if !route.IsCycle() {
+		fmt.Printf("  Output: %s\n", formatAmount(simulation.AmountOut(), route.Hops[len(route.Hops)-1].To))
+		return nil
+	}
+
+	start := route.Hops[0].From
+	profit := new(big.Int).Sub(simulation.AmountOut(), simulation.Amounts[0])
+	fmt.Printf("  Profit: %s (%.2f%%)\n", formatAmount(profit, start), percentGain(simulation.Amounts[0], simulation.AmountOut()))
+
+	weth, err := plan.executor.WETH(ctx)
+	if err != nil {
+		return fmt.Errorf("reading router WETH: %w", err)
+	}
+	if start.Address != weth {
+		fmt.Printf("  Net Profit: not computed, gas is paid in ETH and the cycle is in %s\n", start.Symbol)
+		return nil
+	}
+
+	net := new(big.Int).Sub(profit, gasCost)
+	fmt.Printf("  Net Profit: %s (%.2f%%)\n", formatAmount(net, start), percentGain(simulation.Amounts[0], new(big.Int).Add(simulation.Amounts[0], net)))
+
+	return nil
+}
+
+// executeSwap signs and sends the planned swap, then reports the profit realised
+func executeSwap(cmd *cobra.Command, plan *execution, deadline time.Time) {
+	route := plan.quote.Route
+
+	fmt.Println("\n📤 Sending swap transaction...")
+	result, err := plan.executor.Swap(cmd.Context(), plan.opts, plan.quote, plan.from, deadline)
+	if result != nil && result.Transaction != nil {
+		fmt.Printf("  Transaction: %s\n", result.Transaction.Hash().Hex())
+	}
+	if err != nil {
+		fmt.Printf("Error executing swap: %v\n", err)
+		return
+	}
+	fmt.Printf("  Mined in block %d, gas used %d\n", result.Receipt.BlockNumber, result.Receipt.GasUsed)
+
+	tokenOut := route.Hops[len(route.Hops)-1].To
+	fmt.Println("\n📊 Execution results:")
+	fmt.Printf("  Received: %s (expected %s)\n", formatAmount(result.AmountOut(), tokenOut), formatAmount(plan.quote.AmountOut(), tokenOut))
+	if route.IsCycle() {
+		profit := new(big.Int).Sub(result.AmountOut(), result.AmountIn())
+		fmt.Printf("  Profit: %s (%.2f%%)\n", formatAmount(profit, tokenOut), percentGain(result.AmountIn(), result.AmountOut()))
+	}
+
+	fmt.Println("\n✅ Arbitrage trade executed")
+}
+
+// revertMessage describes a revert reason, which the router may leave empty
+func revertMessage(reason string) string {
+	if reason == "" {
+		return "no reason given"
+	}
+
+	return reason
+}
+
+// effectiveGasPrice returns the --gas-price in wei, or the node's suggestion for "auto"
+func effectiveGasPrice(cmd *cobra.Command, client *ethclient.Client) (*big.Int, error) {
+	opts := &bind.TransactOpts{}
+	if err := cli.ApplyGasFlags(cmd, opts); err != nil {
+		return nil, err
+	}
+	if opts.GasPrice != nil {
+		return opts.GasPrice, nil
+	}
+
+	return client.SuggestGasPrice(cmd.Context())
+}
+
+// printQuote prints the per-hop amounts of a quote, in whole tokens
+func printQuote(quote *swap.Quote) {
+	for i, hop := range quote.Route.Hops {
+		fmt.Printf("  Hop %d: %s → %s via %s\n", i+1,
+			formatAmount(quote.Amounts[i], hop.From), formatAmount(quote.Amounts[i+1], hop.To), hop.PoolName)
+	}
+	tokenOut := quote.Route.Hops[len(quote.Route.Hops)-1].To
+	fmt.Printf("  Minimum Out: %s (%.2f%% slippage)\n", formatAmount(quote.AmountOutMin, tokenOut), float64(quote.SlippageBps)/100)
+}
+
+// formatAmount formats an amount in base units of a route token as whole tokens with its symbol
+func formatAmount(amount *big.Int, token poolreader.Token) string {
+	return fmt.Sprintf("%s %s", tokens.FormatUnits(amount, token.Decimals), token.Symbol)
+}
+
+// percentGain returns how much larger out is than in, as a percentage
+func percentGain(in, out *big.Int) float64 {
+	gain := new(big.Float).Quo(new(big.Float).SetInt(new(big.Int).Sub(out, in)), new(big.Float).SetInt(in))
+	percent, _ := gain.Float64()
+	return percent * 100
+}
+
 func init() {
 	// Command-specific flags
 
-	// Token sequence for the arbitrage trade (default cycle: ETH→USDC→DAI→ETH)
-	ExecuteCmd.Flags().StringSliceVar(&tokenPath, "path", []string{"ETH", "USDC", "DAI", "ETH"}, "Token path for arbitrage (must form a cycle)")
+	// Token sequence for the arbitrage trade (default cycle through built-in pools: eUSD→eEUR→eGBP→eUSD)
+	ExecuteCmd.Flags().StringSliceVar(&tokenPath, "path", []string{"eUSD", "eEUR", "eGBP", "eUSD"}, "Token symbols or addresses for arbitrage, ETH standing for WETH (must form a cycle of registered pools)")
 
 	// Maximum acceptable price slippage percentage (default 0.5%)
 	ExecuteCmd.Flags().Float64Var(&maxSlippage, "slippage", 0.5, "Maximum slippage percentage")
@@ -93,14 +457,19 @@ func init() {
 	// Time limit for transaction execution (default 5 minutes)
 	ExecuteCmd.Flags().UintVar(&executionDeadline, "deadline", 5, "Transaction deadline in minutes")
 
+	// Input amount in whole tokens of the first path token (empty sizes a cycle for maximum profit)
+	ExecuteCmd.Flags().StringVar(&executeAmount, "amount", "", "Amount of the first token to trade: whole tokens, 'max' or a percentage of the balance such as 25% (default: optimal size for a cycle)")
+
 	// Simulation toggle, defaulting to true for safety
 	ExecuteCmd.Flags().BoolVar(&dryRun, "dry-run", true, "Simulate execution without sending transactions")
 
+	// Block to run the dry-run against, for reproducing past opportunities (0 for latest)
+	ExecuteCmd.Flags().Uint64Var(&simulateBlock, "block", 0, "Block number to simulate against in dry-run (0 for latest)")
+
 	// Add this command to the parent arbitrage command
 	ArbitrageCmd.AddCommand(ExecuteCmd)
 }
 
-
 /*
 Execution Setup:
 
@@ -110,9 +479,14 @@ Distinguishes between dry run (simulation) and live execution modes
 
 Execution Flow:
 
-In dry run mode, simulates the calculation and execution process with timing pauses
-Provides a detailed simulation report showing steps in the trade path and expected profits
-For live execution, currently displays a "not yet implemented" message
-The simulation shows how a trade would flow through multiple tokens and return to the original token with profit
+Both modes resolve the token path to pools, quote every hop from current reserves (or a pinned
+block with --block in dry run) and apply the slippage bound and deadline
+Live execution drops a cycle whose profit after slippage is below --min-profit
+Both then run the exact swapExactTokensForTokens transaction through eth_call and eth_estimateGas,
+or after a simulated approval through eth_simulateV1 when the router is not approved yet,
+reporting the router's per-hop amounts or its decoded revert reason, the gas cost and the net profit
+In dry run mode nothing more happens
+For live execution, approves the router when needed, signs with the --keystore-file account (see cli.OpenSigner),
+sends the same transaction through the router, waits for the receipt and reports the amount received and the realised profit
 
-*/
\ No newline at end of file
+*/
@@ -1,22 +1,25 @@
 /*
-	This file represents the core scanning functionality of the arbitrage bot, providing a mechanism to continuously monitor pools and identify profitable trading opportunities based on price imbalances. While currently using simulated data, it's structured to be easily upgraded to interact with real blockchain data.
+	This file represents the core scanning functionality of the arbitrage bot, providing a mechanism to continuously monitor pools and identify profitable trading opportunities based on price imbalances. Pool state comes from the shared poolreader package, so the scanner sees exactly what the other commands see.
 
 */
 
-
 package arbitrage
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"math/big"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
-	"github.com/AnnaGD/go-eth-trade-bot/cmd/constants" 
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/cli"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/poolreader"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/registry"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/swapmath"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/tokens"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/spf13/cobra"
 )
 
@@ -25,15 +28,9 @@ var (
 	outputFormat  string
 	scanTimeLimit uint
 	selectedPools []string
+	watchSync     bool
 )
 
-// PoolReserves represents the reserves in a Uniswap V2 pool
-type PoolReserves struct {
-	Reserve0  *big.Int
-	Reserve1  *big.Int
-	Timestamp uint32
-}
-
 // Defines the main scan command with its usage, descriptions, and run function.
 /*
 	Retrieves configuration from command flags, Establishes an Ethereum client connection,
@@ -48,7 +45,7 @@ type PoolReserves struct {
 var ScanCmd = &cobra.Command{
 	Use:   "scan",
 	Short: "Scan for arbitrage opportunities",
-	Long: `Scan Uniswap V2 pools for potential arbitrage opportunities. This command monitors pool states and identifies imbalances that could be exploited for profit.`,
+	Long:  `Scan Uniswap V2 pools for potential arbitrage opportunities. This command monitors pool states and identifies imbalances that could be exploited for profit.`,
 	Run: func(cmd *cobra.Command, args []string) {
 
 		// Get persistent flags
@@ -66,22 +63,43 @@ var ScanCmd = &cobra.Command{
 			fmt.Println("  Time Limit: None (running until stopped)")
 		}
 
-		// Connect to Ethereum
-		client, err := ethclient.Dial(rpcURL)
+		// Load the pools to monitor: the selected ones, or every registered pool
+		registered, err := cli.OpenRegistry(cmd)
+		if err != nil {
+			fmt.Printf("Error loading pool registry: %v\n", err)
+			return
+		}
+		pools, err := selectPools(registered, selectedPools)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+
+		// Connect to the pool state source (RPC or the deterministic fake)
+		reader, err := openPoolReader(cmd, registered)
 		if err != nil {
 			fmt.Printf("Error connecting to Ethereum: %v\n", err)
 			return
 		}
+		ctx := cmd.Context()
 
-		// If no pools selected, use all pools
-		if len(selectedPools) == 0 {
-			for pool := range constants.UniV2Pools {
-				selectedPools = append(selectedPools, pool)
-			}
+		// Ratios are compared in whole tokens, which needs the decimals of every pool token
+		resolver, err := cli.OpenTokens(cmd, registered, reader)
+		if err != nil {
+			fmt.Printf("Error loading token registry: %v\n", err)
+			return
+		}
+		if err := completePools(ctx, resolver, pools); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+
+		if watchSync {
+			fmt.Printf("Monitoring %d pools on Sync events\n", len(pools))
+		} else {
+			fmt.Printf("Monitoring %d pools with %d second interval\n",
+				len(pools), scanInterval)
 		}
-		
-		fmt.Printf("Monitoring %d pools with %d second interval\n", 
-			len(selectedPools), scanInterval)
 
 		// Setup for graceful termination
 		sigs := make(chan os.Signal, 1)
@@ -92,9 +110,24 @@ var ScanCmd = &cobra.Command{
 		fmt.Println("⏳ Scanning started at", startTime.Format(time.RFC3339))
 		fmt.Println("Press Ctrl+C to stop scanning")
 
-		// Create a timer for the scan interval
-		ticker := time.NewTicker(time.Duration(scanInterval) * time.Second)
-		defer ticker.Stop()
+		// Either poll every interval or re-evaluate on Sync events
+		var ticks <-chan time.Time
+		var events <-chan *poolreader.Batch
+		var watchErrs <-chan error
+		if watchSync {
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+
+			events, watchErrs, err = watchPools(ctx, reader, pools)
+			if err != nil {
+				fmt.Printf("Error watching pools: %v\n", err)
+				return
+			}
+		} else {
+			ticker := time.NewTicker(time.Duration(scanInterval) * time.Second)
+			defer ticker.Stop()
+			ticks = ticker.C
+		}
 
 		// Create a timeout if specified
 		var timeout <-chan time.Time
@@ -106,68 +139,53 @@ var ScanCmd = &cobra.Command{
 		opportunityCount := 0
 		scanCount := 0
 
+		// In watch mode, start from a full scan so pools that never move are still evaluated once
+		if watchSync {
+			scanCount++
+			batch, err := readPools(ctx, reader, pools)
+			if err != nil {
+				fmt.Printf("Error reading pools: %v\n", err)
+				return
+			}
+			fmt.Printf("\nInitial pool state at block %d\n", batch.BlockNumber)
+			opportunityCount += reportPools(batch, pools, minProfit, opportunityCount)
+		}
+
 		for {
 			select {
-			case <-ticker.C:
+			case <-ticks:
 				scanCount++
 				fmt.Printf("\n[%s] Scan #%d: Checking for arbitrage opportunities...\n",
 					time.Now().Format("15:04:05"), scanCount)
 
-				// Check each selected pool
-				for _, poolName := range selectedPools {
-					poolAddress, exists := constants.UniV2Pools[poolName]
-					if !exists {
-						fmt.Printf("Pool %s not found\n", poolName)
-						continue
-					}
-					
-					targetRatio, hasTarget := constants.TargetRatios[poolName]
-					if !hasTarget {
-						fmt.Printf("No target ratio for %s, skipping\n", poolName)
-						continue
-					}
-					
-					// Get pool reserves
-					reserves, err := getPoolReserves(client, poolAddress)
-					if err != nil {
-						fmt.Printf("Error reading %s: %v\n", poolName, err)
-						continue
-					}
-					
-					// Calculate current ratio
-					currentRatio := calculateCurrentRatio(reserves)
-					
-					// Calculate imbalance percentage
-					imbalancePercent := ((currentRatio - targetRatio) / targetRatio) * 100
-					
-					fmt.Printf("%s: Current Ratio: %.4f (Target: %.4f)\n", 
-						poolName, currentRatio, targetRatio)
-					
-					// Check if there's a significant imbalance
-					if abs(imbalancePercent) > 1.0 {  // More than 1% imbalance
-						fmt.Printf("  ✅ OPPORTUNITY: %.2f%% imbalance detected!\n", imbalancePercent)
-						
-						// Calculate potential profit
-						profitPercent := calculatePotentialProfit(currentRatio, targetRatio)
-						
-						// Check if profit meets minimum threshold
-						if profitPercent >= minProfit {
-							opportunityCount++
-							fmt.Printf("  💰 Opportunity #%d - Potential Profit: %.2f%%\n", 
-								opportunityCount, profitPercent)
-							
-							// In a full implementation, you would:
-							// 1. Calculate optimal trade amounts
-							// 2. Execute the trade if in auto mode
-							// 3. Log the opportunity details
-						} else {
-							fmt.Printf("  ❌ Profit too low: %.2f%% (min: %.2f%%)\n", profitPercent, minProfit)
-						}
-					} else {
-						fmt.Printf("  ❌ No significant imbalance\n")
-					}
+				// Read every selected pool in one round trip, pinned to a single block
+				batch, err := readPools(ctx, reader, pools)
+				if err != nil {
+					fmt.Printf("Error reading pools: %v\n", err)
+					continue
 				}
-				
+				fmt.Printf("Pool state at block %d\n", batch.BlockNumber)
+
+				opportunityCount += reportPools(batch, pools, minProfit, opportunityCount)
+
+			case batch := <-events:
+				// Only the pools whose reserves moved are re-evaluated
+				scanCount++
+				if batch.Reorg {
+					fmt.Printf("\n[%s] ⚠️ Reorg: re-read %d pools\n",
+						time.Now().Format("15:04:05"), len(batch.Reserves)+len(batch.Errors))
+				} else {
+					fmt.Printf("\n[%s] Block %d: %d pools moved\n",
+						time.Now().Format("15:04:05"), batch.BlockNumber, len(batch.Reserves))
+				}
+
+				opportunityCount += reportPools(batch, movedPools(batch, pools), minProfit, opportunityCount)
+
+			case err := <-watchErrs:
+				fmt.Printf("\nError watching pools: %v\n", err)
+				fmt.Printf("Found %d opportunities in %d scans\n", opportunityCount, scanCount)
+				return
+
 			case <-timeout:
 				if scanTimeLimit > 0 {
 					fmt.Printf("\n\n⏱️ Scan time limit (%d minutes) reached\n", scanTimeLimit)
@@ -184,53 +202,195 @@ var ScanCmd = &cobra.Command{
 	},
 }
 
-// This is a placeholder function that simulates getting pool reserves
+// reportPools prints the check of each pool in a batch and returns how many opportunities it found.
+// Opportunities are numbered on from previousCount.
+func reportPools(batch *poolreader.Batch, pools []*registry.Pool, minProfit float64, previousCount int) int {
+	found := 0
+
+	for _, pool := range pools {
+		check, err := checkPool(batch, pool)
+		if errors.Is(err, errNoTarget) {
+			fmt.Printf("No target ratio for %s, skipping\n", pool.Name)
+			continue
+		}
+		if err != nil {
+			fmt.Printf("Error reading %s: %v\n", pool.Name, err)
+			continue
+		}
+
+		fmt.Printf("%s: Current Ratio: %.4f (Target: %.4f)\n",
+			pool.Name, check.CurrentRatio, check.TargetRatio)
+
+		// Check if there's a significant imbalance
+		if check.Significant() {
+			fmt.Printf("  ✅ OPPORTUNITY: %.2f%% imbalance detected!\n", check.ImbalancePercent)
+
+			// Check if profit meets minimum threshold; pools already at target have no trade
+			if check.Trade == nil {
+				fmt.Printf("  ❌ No trade back to target\n")
+			} else if check.ProfitPercent >= minProfit {
+				found++
+				fmt.Printf("  💰 Opportunity #%d - Potential Profit: %.2f%%\n",
+					previousCount+found, check.ProfitPercent)
+				fmt.Printf("  📐 Trade: %s\n", describeTrade(check.Trade, pool))
+			} else {
+				fmt.Printf("  ❌ Profit too low: %.2f%% (min: %.2f%%)\n", check.ProfitPercent, minProfit)
+			}
+		} else {
+			fmt.Printf("  ❌ No significant imbalance\n")
+		}
+	}
+
+	return found
+}
+
+// watchPools starts a Sync watcher for the pools in the background.
+// Batches of moved pools arrive on the first channel; the watcher's terminal error on the second.
+func watchPools(ctx context.Context, reader poolreader.PoolReader, pools []*registry.Pool) (<-chan *poolreader.Batch, <-chan error, error) {
+	watcher, err := poolreader.NewSyncWatcher(reader, poolAddresses(pools))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	events := make(chan *poolreader.Batch)
+	errs := make(chan error, 1)
+	go func() {
+		errs <- watcher.Run(ctx, events)
+	}()
+
+	return events, errs, nil
+}
+
+// movedPools returns the pools present in a batch, in pools order
+func movedPools(batch *poolreader.Batch, pools []*registry.Pool) []*registry.Pool {
+	var moved []*registry.Pool
+	for _, pool := range pools {
+		_, updated := batch.Reserves[pool.Address]
+		_, failed := batch.Errors[pool.Address]
+		if updated || failed {
+			moved = append(moved, pool)
+		}
+	}
+
+	return moved
+}
+
+// selectPools returns the registered pools named by --pools, or every registered pool
+func selectPools(registered *registry.Registry, names []string) ([]*registry.Pool, error) {
+	if len(names) == 0 {
+		return registered.Pools(), nil
+	}
+
+	pools := make([]*registry.Pool, 0, len(names))
+	for _, name := range names {
+		pool, ok := registered.Lookup(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown pool %s (see tradebot pools list)", name)
+		}
+		pools = append(pools, pool)
+	}
+
+	return pools, nil
+}
+
+// poolAddresses returns the address of each pool
+func poolAddresses(pools []*registry.Pool) []common.Address {
+	addresses := make([]common.Address, len(pools))
+	for i, pool := range pools {
+		addresses[i] = pool.Address
+	}
+
+	return addresses
+}
+
+// poolCheck is the result of comparing one pool's current ratio to its target
+type poolCheck struct {
+	Pool             *registry.Pool
+	CurrentRatio     float64
+	TargetRatio      float64
+	ImbalancePercent float64
+	ProfitPercent    float64
+	Trade            *swapmath.Rebalance // trade back to the target ratio, nil when balanced
+}
+
+// errNoTarget is returned by checkPool for pools without a target ratio in the registry
+var errNoTarget = errors.New("no target ratio")
+
+// completePools replaces the registered tokens of the pools with their resolved
+// token0 and token1, so that checkPool can scale the ratio of pools whose two
+// tokens have different decimals. Pools registered with their tokens the other
+// way round, as a built-in pool name can give them, are reordered along with
+// their target ratio.
+func completePools(ctx context.Context, resolver *tokens.Resolver, pools []*registry.Pool) error {
+	for _, pool := range pools {
+		token0, token1, err := resolver.PoolTokens(ctx, pool)
+		if err != nil {
+			return err
+		}
+
+		pool.Orient(token0.Registry(), token1.Registry())
+	}
+
+	return nil
+}
+
+// readPools reads the reserves of the pools with a single batched, block-pinned call
+func readPools(ctx context.Context, reader poolreader.PoolReader, pools []*registry.Pool) (*poolreader.Batch, error) {
+	return reader.ReservesBatch(ctx, poolAddresses(pools))
+}
+
+// checkPool compares a pool's reserves from a batch read to its target ratio
 
 /*
-	Currently returns deterministic mock values based on pool addresses
-	Is meant to be replaced with actual Uniswap V2 contract calls in production
-	Returns a `PoolReserves` struct with reserve values and timestamp
+	Used by both ScanCmd and AutoCmd so that the two commands agree on what counts as an opportunity
+	Returns errNoTarget when the pool has no target ratio to compare against
 */
 
-func getPoolReserves(client *ethclient.Client, poolAddress string) (*PoolReserves, error) {
-	
-	// Convert address string to common.Address
-	addr := common.HexToAddress(poolAddress)
-	
-	// Create deterministic values based on the address
-	// This creates different values for different pools but stays consistent
-	reserve0 := new(big.Int).SetBytes(addr.Bytes()[:16])
-	reserve0.Mod(reserve0, big.NewInt(1000))
-	reserve0.Add(reserve0, big.NewInt(500))
-	reserve0.Mul(reserve0, big.NewInt(1000000000000000000)) // 18 decimals
-	
-	reserve1 := new(big.Int).SetBytes(addr.Bytes()[16:])
-	reserve1.Mod(reserve1, big.NewInt(1000))
-	reserve1.Add(reserve1, big.NewInt(500))
-	reserve1.Mul(reserve1, big.NewInt(1000000000000000000)) // 18 decimals
-	
-	reserves := &PoolReserves{
-		Reserve0:  reserve0,
-		Reserve1:  reserve1,
-		Timestamp: uint32(time.Now().Unix()),
+func checkPool(batch *poolreader.Batch, pool *registry.Pool) (*poolCheck, error) {
+	if !pool.HasTarget() {
+		return nil, errNoTarget
+	}
+	targetRatio := pool.TargetRatio
+
+	// Get pool reserves from the batch
+	if err, failed := batch.Errors[pool.Address]; failed {
+		return nil, err
+	}
+	reserves, ok := batch.Reserves[pool.Address]
+	if !ok {
+		return nil, fmt.Errorf("pool %s was not read", pool.Name)
+	}
+
+	// Calculate current ratio in whole tokens; pools whose tokens have different
+	// decimals would otherwise be off by a power of ten
+	decimals0, decimals1 := pool.Token0.Decimals, pool.Token1.Decimals
+	currentRatio := tokens.ScaleRatio(poolreader.Ratio(reserves), decimals0, decimals1)
+
+	check := &poolCheck{
+		Pool:         pool,
+		CurrentRatio: currentRatio,
+		TargetRatio:  targetRatio,
+
+		// Calculate imbalance percentage
+		ImbalancePercent: ((currentRatio - targetRatio) / targetRatio) * 100,
+	}
+
+	// Calculate the trade back to target and its potential profit
+	trade, err := calculatePotentialProfit(reserves, tokens.UnscaleRatio(targetRatio, decimals0, decimals1), pool.FeeBps)
+	if err != nil && !errors.Is(err, swapmath.ErrBalanced) {
+		return nil, err
 	}
-	
-	return reserves, nil
+	if trade != nil {
+		check.Trade = trade
+		check.ProfitPercent = trade.ProfitPercent
+	}
+
+	return check, nil
 }
 
-// calculateCurrentRatio calculates the current ratio of token0 to token1
-func calculateCurrentRatio(reserves *PoolReserves) float64 {
-
-	// Convert big ints to float64 for ratio calculation
-	reserve0Float := new(big.Float).SetInt(reserves.Reserve0)
-	reserve1Float := new(big.Float).SetInt(reserves.Reserve1)
-	
-	// Calculate ratio
-	ratio := new(big.Float).Quo(reserve0Float, reserve1Float)
-	
-	// Convert to float64
-	result, _ := ratio.Float64()
-	return result
+// Significant reports whether the imbalance is large enough to act on (more than 1%)
+func (c *poolCheck) Significant() bool {
+	return abs(c.ImbalancePercent) > 1.0
 }
 
 // abs returns the absolute value of x
@@ -241,13 +401,29 @@ func abs(x float64) float64 {
 	return x
 }
 
-// calculatePotentialProfit calculates potential profit from an arbitrage
-func calculatePotentialProfit(currentRatio, targetRatio float64) float64 {
-	// Profit model: larger imbalance = higher profit potential
-	imbalancePercent := abs((currentRatio - targetRatio) / targetRatio) * 100
-	
-	// Discount for transaction costs
-	return imbalancePercent * 0.7  // 70% of imbalance becomes profit (after gas)
+// calculatePotentialProfit solves the trade that moves the pool back to its target ratio,
+// given in base units like the reserves
+
+/*
+	The trade is sized by swapmath.SolveRebalance with the pool's fee, simulated through the
+	real reserves, and its output valued at the target ratio. A balanced pool has no trade.
+*/
+
+func calculatePotentialProfit(reserves *poolreader.Reserves, targetRatio float64, feeBps uint64) (*swapmath.Rebalance, error) {
+	return swapmath.SolveRebalance(reserves.Reserve0, reserves.Reserve1, targetRatio, feeBps)
+}
+
+// describeTrade prints the size of a rebalancing trade in whole tokens of the pool
+func describeTrade(trade *swapmath.Rebalance, pool *registry.Pool) string {
+	in, out := pool.Token1, pool.Token0
+	if trade.ZeroForOne {
+		in, out = pool.Token0, pool.Token1
+	}
+
+	return fmt.Sprintf("sell %s %s for %s %s, profit %s %s vs target",
+		tokens.FormatUnits(trade.AmountIn, in.Decimals), in.Symbol,
+		tokens.FormatUnits(trade.AmountOut, out.Decimals), out.Symbol,
+		tokens.FormatUnits(trade.Profit, in.Decimals), in.Symbol)
 }
 
 func init() {
@@ -257,14 +433,17 @@ func init() {
 	ScanCmd.Flags().UintVar(&scanInterval, "interval", 30, "Scan interval in seconds")
 
 	// Specific pools to monitor (default: all)
-	ScanCmd.Flags().StringSliceVar(&selectedPools, "pools", []string{}, "Pool names to monitor (default: all)")
+	ScanCmd.Flags().StringSliceVar(&selectedPools, "pools", []string{}, "Pool names or addresses to monitor (default: all registered pools)")
 
 	// Output format (text or JSON)
 	ScanCmd.Flags().StringVar(&outputFormat, "output", "text", "Output format (text, json)")
 
+	// Re-evaluate pools on Uniswap V2 Sync events instead of polling on a fixed interval
+	ScanCmd.Flags().BoolVar(&watchSync, "watch", false, "Re-evaluate pools on Sync events instead of polling every interval")
+
 	// Duration to run the scan (0 for unlimited)
 	ScanCmd.Flags().UintVar(&scanTimeLimit, "time-limit", 0, "Time limit for scanning in minutes (0 for no limit)")
 
 	// Add this command to the parent arbitrage command
 	ArbitrageCmd.AddCommand(ScanCmd)
-}
\ No newline at end of file
+}
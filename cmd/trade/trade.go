@@ -1,12 +1,24 @@
 package trade
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"math/big"
 	"math/rand"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/AnnaGD/go-eth-trade-bot/cmd/constants"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/approval"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/cli"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/poolreader"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/registry"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/swap"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/swapmath"
+	"github.com/AnnaGD/go-eth-trade-bot/cmd/internal/tokens"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/spf13/cobra"
@@ -22,35 +34,83 @@ var (
 	deadlineMin uint
 
 	// Pool specific parameters
-	targetPool    string
-	imbalanceMode bool
+	targetPool      string
+	imbalanceMode   bool
+	targetImbalance string
 )
 
-// Execute command for trading
+// Trade command - parent command for the trading subcommands
 var TradeCmd = &cobra.Command{
+	Use:   "trade",
+	Short: "Trade tokens on Uniswap V2",
+	Long:  `The trade command groups the subcommands that swap tokens directly on Uniswap V2 pools, either as regular trades or to create imbalances for testing the arbitrage bot.`,
+}
+
+// Execute command for trading
+var ExecuteCmd = &cobra.Command{
 	Use:   "execute",
 	Short: "Execute a trade on Uniswap V2",
-	Long: `Execute a token swap on Uniswap V2 liquidity pools. This command allows you to swap tokens or create imbalances for testing.`,
+	Long: `Execute a token swap on Uniswap V2 liquidity pools. This command allows you to swap tokens or create imbalances for testing.
+
+Standard trades swap --amount of --token-in for --token-out along the best direct or one-intermediate-hop route among the registered pools, bounded by --slippage and --deadline. ETH is swapped through the router's WETH. The swap is simulated with eth_call before it is signed and sent.
+
+With --imbalance, one token of --pool (a random pool by default) is sold for the other to seed arbitrage opportunities. --target-imbalance sizes the trade to leave the pool's ratio that percentage off its target ratio from the pool registry, or off its current ratio for pools without a target; otherwise --amount is sold. The ratio is reported before and after the trade.`,
 	Run: func(cmd *cobra.Command, args []string) {
 
 		// Get persistent flags
-		rpcURL, _ := cmd.Flags().GetString("rpc-url")
 		wallet, _ := cmd.Flags().GetString("wallet")
 
-		// keystoreFile, _ := cmd.Flags().GetString("keystore-file")
 		gasPrice, _ := cmd.Flags().GetString("gas-price")
 		gasLimit, _ := cmd.Flags().GetUint64("gas-limit")
 
-		// Connect to Network -- on this case Ethereum
-		client, err := ethclient.Dial(rpcURL)
+		swapAmount, err := tokens.ParseAmount(amount)
+		if err != nil {
+			fmt.Printf("❌ Invalid --amount: %v\n", err)
+			return
+		}
+
+		registered, err := cli.OpenRegistry(cmd)
+		if err != nil {
+			fmt.Printf("Error loading pool registry: %v\n", err)
+			return
+		}
+
+		// Connect to the pool state source (RPC or the deterministic fake)
+		rpcURL, _ := cmd.Flags().GetString("rpc-url")
+		fake, _ := cmd.Flags().GetBool("fake-pools")
+		var reader poolreader.PoolReader = poolreader.NewFake(registered)
+		var client *ethclient.Client
+		if !fake {
+			if client, err = ethclient.DialContext(cmd.Context(), rpcURL); err != nil {
+				fmt.Printf("Error connecting to Ethereum: %v\n", err)
+				return
+			}
+			defer client.Close()
+			reader = poolreader.NewLive(client)
+		}
+
+		// Resolve symbols and decimals through the token registry and the chain
+		resolver, err := cli.OpenTokens(cmd, registered, reader)
 		if err != nil {
-			fmt.Printf("Error connecting to Ethereum: %v\n", err)
+			fmt.Printf("Error loading token registry: %v\n", err)
+			return
+		}
+
+		ctx := cmd.Context()
+		if _, err := swap.SlippageBps(slippage); err != nil {
+			fmt.Printf("❌ Invalid --slippage: %v\n", err)
 			return
 		}
 
 		// Create a random number generator with its own source
 		rng := rand.New(rand.NewSource(time.Now().UnixNano()))
 
+		// Check the tokens and the amount before the signing account is unlocked
+		var pool *registry.Pool
+		var in, out, token0, token1 tokens.Token
+		var sellZero bool
+		var deviation float64
+		sell := &in
 		if imbalanceMode {
 			// Create deliberate imbalances for testing
 			fmt.Println("🔄 Starting imbalance trade...")
@@ -59,119 +119,438 @@ var TradeCmd = &cobra.Command{
 			if targetPool == "" {
 
 				// Choose random pool
-				pools := make([]string, 0, len(constants.UniV2Pools))
-				for pool := range constants.UniV2Pools {
-					pools = append(pools, pool)
-				}
+				pools := registered.Names()
 				// Use the local RNG instead of global rand
 				targetPool = pools[rng.Intn(len(pools))]
 			}
 
 			// Get pool address
-			poolAddress, exists := constants.UniV2Pools[targetPool]
+			var exists bool
+			pool, exists = registered.Lookup(targetPool)
 			if !exists {
 				fmt.Printf("❌ Pool %s not found\n", targetPool)
 				return
 			}
+			targetPool = pool.Name
 
-			fmt.Printf("🎯 Target Pool: %s (%s)\n", targetPool, poolAddress)
-
-			// Get token names from pool name
-			tokens := parsePoolTokens(targetPool)
-
-			// Choose which tokens to sell(randomly)
-			tokenToSell := tokens[rng.Intn(2)]
-			tokenToBuy := tokens[1-rng.Intn(2)] // The other token
-
-			fmt.Printf("💱 Creating imbalance by selling %s to buy %s\n", tokenToSell, tokenToBuy)
-			fmt.Printf("💰 Amount: %s\n", amount)
+			fmt.Printf("🎯 Target Pool: %s (%s)\n", targetPool, pool.Address.Hex())
 
-			// Execute the trade
-			fmt.Println("Executing imbalance trade...")
+			if token0, token1, err = resolver.PoolTokens(ctx, pool); err != nil {
+				fmt.Printf("❌ Error: %v\n", err)
+				return
+			}
 
-			// TODO: Connect to the pool contract and execute the swap
-			// This would be the same function that would be used for regular trading
-			// For demo, we'll simulate this
-			executeSwap(client, common.HexToAddress(poolAddress), tokenToSell, tokenToBuy, amount)
+			// Sell one token of the pool for the other, chosen at random
+			sellZero = rng.Intn(2) == 0
+			sell = &token1
+			if sellZero {
+				sell = &token0
+			}
 
-			fmt.Println("✅ Trade complete! Pool is now imbalanced.")
-			fmt.Println("Arbitrage opportunity created for testing.")
+			// A target imbalance sizes the trade instead of --amount
+			if targetImbalance != "" {
+				if deviation, err = parsePercent(targetImbalance); err != nil {
+					fmt.Printf("❌ Invalid --target-imbalance: %v\n", err)
+					return
+				}
+				sell = nil
+			}
 		} else {
 
 			// Regular Trading Mode: Normal token swapping
 			fmt.Println("🔄 Executing standard trade...")
-			fmt.Printf("  Token In: %s\n", tokenIn)
-			fmt.Printf("  Token Out: %s\n", tokenOut)
-			fmt.Printf("  Amount: %s\n", amount)
-			fmt.Printf("  Slippage: %.2f%%\n", slippage)
-			fmt.Printf("  Deadline: %d minutes\n", deadlineMin)
-			fmt.Printf("  RPC URL: %s\n", rpcURL)
-			fmt.Printf("  Wallet: %s\n", wallet)
-			fmt.Printf("  Gas Price: %s\n", gasPrice)
-			fmt.Printf("  Gas Limit: %d\n", gasLimit)
-
-			// TODO: Implement the actual trade execution
-			// This would involve:
-			// 1. Resolving token addresses
-			// 2. Setting up the transaction
-			// 3. Calculating minimum output with slippage
-			// 4. Executing the swap
-			fmt.Println("⚠️ Standard trade execution not fully implemented yet")
+			if tokenOut == "" {
+				fmt.Println("❌ --token-out is required")
+				return
+			}
+			if in, err = resolver.Resolve(ctx, tokenIn); err != nil {
+				fmt.Printf("❌ Error resolving --token-in: %v\n", err)
+				return
+			}
+			if out, err = resolver.Resolve(ctx, tokenOut); err != nil {
+				fmt.Printf("❌ Error resolving --token-out: %v\n", err)
+				return
+			}
+			fmt.Printf("  Token In: %s\n", describeToken(in))
+			fmt.Printf("  Token Out: %s\n", describeToken(out))
+		}
+
+		// Max and percentages wait for the balance of the signing account
+		if sell != nil && !swapAmount.RelativeToBalance() {
+			if _, err := swapAmount.BaseUnits(sell.Decimals, nil); err != nil {
+				fmt.Printf("❌ Invalid --amount: %v\n", err)
+				return
+			}
+		}
+
+		// Unlock the signing account and bind the router over the same connection;
+		// simulated pools have no chain to sign for
+		chain, err := openChain(cmd, client)
+		if err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			return
+		}
+		if chain != nil {
+			fmt.Printf("🔐 Signing as %s\n", chain.opts.From.Hex())
+		}
 
+		if imbalanceMode {
+			if err := imbalanceTrade(cmd, reader, chain, pool, token0, token1, sellZero, deviation, swapAmount); err != nil {
+				fmt.Printf("❌ Error: %v\n", err)
+			}
+			return
+		}
+
+		amountIn, err := amountUnits(ctx, chain, swapAmount, in)
+		if err != nil {
+			fmt.Printf("❌ Invalid --amount: %v\n", err)
+			return
+		}
+		fmt.Printf("  Amount: %s (%s base units)\n", in.Format(amountIn), amountIn)
+		fmt.Printf("  Slippage: %.2f%%\n", slippage)
+		fmt.Printf("  Deadline: %d minutes\n", deadlineMin)
+		fmt.Printf("  RPC URL: %s\n", rpcURL)
+		fmt.Printf("  Wallet: %s\n", wallet)
+		fmt.Printf("  Gas Price: %s\n", gasPrice)
+		fmt.Printf("  Gas Limit: %d\n", gasLimit)
+
+		if err := standardTrade(cmd, reader, registered, chain, in, out, amountIn); err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			return
 		}
 	},
 }
 
-func parsePoolTokens(poolName string) []string {
-	tokens := []string{"TokenA", "TokenB"}
+// standardTrade swaps amountIn of in for out along the best direct or
+// one-intermediate-hop route among the registered pools. The swap is run
+// through eth_call first and only sent, signed by the chain's account, when it succeeds.
+// Native ETH is swapped through the router's WETH. A nil chain only quotes the swap.
+func standardTrade(cmd *cobra.Command, reader poolreader.PoolReader, registered *registry.Registry, chain *chain, in, out tokens.Token, amountIn *big.Int) error {
+	ctx := cmd.Context()
+
+	slippageBps, err := swap.SlippageBps(slippage)
+	if err != nil {
+		return err
+	}
+	if in.IsNative() && out.IsNative() {
+		return errors.New("--token-in and --token-out are both the native currency")
+	}
+
+	// Simulated pools have no router to ask for WETH
+	weth := common.HexToAddress(constants.WETH)
+	if chain != nil {
+		if weth, err = chain.executor.WETH(ctx); err != nil {
+			return fmt.Errorf("reading router WETH: %w", err)
+		}
+	}
+
+	from, to := in.Address, out.Address
+	if in.IsNative() {
+		from = weth
+	}
+	if out.IsNative() {
+		to = weth
+	}
+
+	fmt.Println("\n🧭 Finding the best route...")
+	routes, err := swap.FindRoutes(ctx, reader, registered, from, to)
+	if err != nil {
+		return err
+	}
+	quote, err := swap.BestQuote(ctx, reader, routes, amountIn, slippageBps)
+	if err != nil {
+		return err
+	}
+	quote.NativeIn, quote.NativeOut = in.IsNative(), out.IsNative()
+
+	for i, hop := range quote.Route.Hops {
+		fmt.Printf("  Hop %d: %s %s → %s %s via %s\n", i+1,
+			tokens.FormatUnits(quote.Amounts[i], hop.From.Decimals), hop.From.Symbol,
+			tokens.FormatUnits(quote.Amounts[i+1], hop.To.Decimals), hop.To.Symbol, hop.PoolName)
+	}
+	fmt.Printf("  Expected Out: %s\n", out.Format(quote.AmountOut()))
+	fmt.Printf("  Minimum Out: %s (%.2f%% slippage)\n", out.Format(quote.AmountOutMin), float64(slippageBps)/100)
+
+	if chain == nil {
+		fmt.Println("\n⚠️ Pools are simulated: the swap was not sent. Drop --fake-pools to trade on-chain.")
+		return nil
+	}
+
+	result, err := chain.send(cmd, quote, in, out)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✅ Received %s (expected %s)\n", out.Format(result.AmountOut()), out.Format(quote.AmountOut()))
+
+	return nil
+}
+
+// imbalanceTrade sells one token of the pool for the other, token0 when
+// sellZero is set, and reports the pool's ratio before and after. A deviation
+// from --target-imbalance sizes the trade to leave the ratio that percentage
+// above (selling token0) or below (selling token1) the pool's target ratio, or
+// its current ratio when the pool has no target; a pool already further off
+// its target on that side is traded back to it the other way. Otherwise it
+// sells --amount. It goes through the same router path as a standard trade.
+func imbalanceTrade(cmd *cobra.Command, reader poolreader.PoolReader, chain *chain, pool *registry.Pool, token0, token1 tokens.Token, sellZero bool, deviation float64, swapAmount tokens.Amount) error {
+	ctx := cmd.Context()
+
+	slippageBps, err := swap.SlippageBps(slippage)
+	if err != nil {
+		return err
+	}
+
+	// The target ratio is token0 per token1 of the pool's on-chain tokens
+	pool.Orient(token0.Registry(), token1.Registry())
+
+	// Show the pool state every other command sees before trading
+	reserves, err := reader.Reserves(ctx, pool.Address)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", pool.Name, err)
+	}
+	fmt.Printf("📊 Reserves: %s / %s (ratio %.4f)\n",
+		token0.Format(reserves.Reserve0), token1.Format(reserves.Reserve1), poolRatio(reserves, token0, token1))
+
+	var amountIn *big.Int
+	if deviation > 0 {
+		// Deviate from the registered target, in base units, or else from the current ratio
+		reference, from := poolreader.Ratio(reserves), "current"
+		if pool.HasTarget() {
+			reference, from = tokens.UnscaleRatio(pool.TargetRatio, token0.Decimals, token1.Decimals), "target"
+		}
+
+		// Selling token0 raises reserve0/reserve1, selling token1 lowers it
+		target := reference * (1 - deviation/100)
+		if sellZero {
+			target = reference * (1 + deviation/100)
+		}
+		trade, err := swapmath.SolveRebalance(reserves.Reserve0, reserves.Reserve1, target, pool.FeeBps)
+		if errors.Is(err, swapmath.ErrBalanced) {
+			return fmt.Errorf("%s is already %.2f%% off its %s ratio", pool.Name, deviation, from)
+		}
+		if err != nil {
+			return fmt.Errorf("sizing trade: %w", err)
+		}
+		amountIn, sellZero = trade.AmountIn, trade.ZeroForOne
+		fmt.Printf("🎚️ Target Imbalance: %.2f%% off the %s ratio %.4f, at %.4f\n", deviation, from,
+			tokens.ScaleRatio(reference, token0.Decimals, token1.Decimals), tokens.ScaleRatio(target, token0.Decimals, token1.Decimals))
+	}
+
+	sell, buy := token0, token1
+	if !sellZero {
+		sell, buy = token1, token0
+	}
+	if amountIn == nil {
+		if amountIn, err = amountUnits(ctx, chain, swapAmount, sell); err != nil {
+			return fmt.Errorf("invalid --amount: %w", err)
+		}
+	}
+
+	hop := swap.Hop{
+		PoolName:   pool.Name,
+		Pool:       pool.Address,
+		From:       poolreader.Token{Address: sell.Address, Symbol: sell.Symbol, Decimals: sell.Decimals},
+		To:         poolreader.Token{Address: buy.Address, Symbol: buy.Symbol, Decimals: buy.Decimals},
+		FeeBps:     pool.FeeBps,
+		ZeroForOne: sellZero,
+	}
+	quote, err := swap.QuoteRoute(ctx, reader, &swap.Route{Hops: []swap.Hop{hop}}, amountIn, slippageBps)
+	if err != nil {
+		return fmt.Errorf("quoting trade: %w", err)
+	}
+
+	// The pool keeps the whole input, fee included, and pays out the output
+	expected := &poolreader.Reserves{Reserve0: new(big.Int).Sub(reserves.Reserve0, quote.AmountOut()), Reserve1: new(big.Int).Add(reserves.Reserve1, amountIn)}
+	if sellZero {
+		expected = &poolreader.Reserves{Reserve0: new(big.Int).Add(reserves.Reserve0, amountIn), Reserve1: new(big.Int).Sub(reserves.Reserve1, quote.AmountOut())}
+	}
+
+	fmt.Printf("💱 Creating imbalance by selling %s for %s\n", sell.Format(amountIn), buy.Format(quote.AmountOut()))
+	fmt.Printf("📈 Expected Ratio After: %.4f (%+.2f%%)\n",
+		poolRatio(expected, token0, token1), ratioChange(reserves, expected))
+
+	if chain == nil {
+		fmt.Println("\n⚠️ Pools are simulated: the swap was not sent. Drop --fake-pools to trade on-chain.")
+		return nil
+	}
+
+	fmt.Println("Executing imbalance trade...")
+	if _, err := chain.send(cmd, quote, sell, buy); err != nil {
+		return err
+	}
+
+	after, err := reader.Reserves(ctx, pool.Address)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", pool.Name, err)
+	}
+	fmt.Printf("📊 Ratio: %.4f → %.4f (%+.2f%%)\n",
+		poolRatio(reserves, token0, token1), poolRatio(after, token0, token1), ratioChange(reserves, after))
+	fmt.Println("✅ Trade complete! Pool is now imbalanced.")
+	fmt.Println("Arbitrage opportunity created for testing.")
+
+	return nil
+}
+
+// poolRatio is the reserve0/reserve1 ratio of a pool in whole tokens
+func poolRatio(reserves *poolreader.Reserves, token0, token1 tokens.Token) float64 {
+	return tokens.ScaleRatio(poolreader.Ratio(reserves), token0.Decimals, token1.Decimals)
+}
+
+// ratioChange returns how much the reserve ratio moved from before to after, as a percentage
+func ratioChange(before, after *poolreader.Reserves) float64 {
+	return (poolreader.Ratio(after)/poolreader.Ratio(before) - 1) * 100
+}
+
+// parsePercent parses a percentage such as "3%" or "3", which must be between 0 and 100
+func parsePercent(value string) (float64, error) {
+	percent, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(value), "%"), 64)
+	if err != nil || percent <= 0 || percent >= 100 {
+		return 0, fmt.Errorf("%q is not a percentage between 0 and 100", value)
+	}
+
+	return percent, nil
+}
+
+// chain is the connection swaps are sent over: the client every read shares,
+// the router bound on it and the signing options
+type chain struct {
+	client   *ethclient.Client
+	executor *swap.Executor
+	opts     *bind.TransactOpts
+}
+
+// openChain unlocks the signing account and binds the --router on client. It
+// returns nil with --fake-pools, whose simulated pools have no chain to send swaps to.
+func openChain(cmd *cobra.Command, client *ethclient.Client) (*chain, error) {
+	router, _ := cmd.Flags().GetString("router")
+	if !common.IsHexAddress(router) {
+		return nil, fmt.Errorf("invalid router address %q", router)
+	}
+	if client == nil {
+		return nil, nil
+	}
+
+	opts, err := cli.Transactor(cmd, client)
+	if err != nil {
+		return nil, err
+	}
+
+	executor, err := swap.NewExecutor(client, common.HexToAddress(router))
+	if err != nil {
+		return nil, fmt.Errorf("binding router: %w", err)
+	}
+
+	return &chain{client: client, executor: executor, opts: opts}, nil
+}
 
-	// Extract token names from format like "eEUR_eAUD_Pool"
-	if len(poolName) > 10 {
-		parts := strings.Split(poolName, "-")
-		if len(parts) >= 2 {
-			tokens[0] = parts[0]
-			tokens[1] = parts[1]
+// send runs the swap through eth_call and, when it succeeds, approves the
+// router for an ERC-20 input as selected by --approval, signs and sends the
+// swap and waits for the receipt
+func (c *chain) send(cmd *cobra.Command, quote *swap.Quote, in, out tokens.Token) (*swap.Result, error) {
+	ctx := cmd.Context()
+
+	mode, err := cli.ApprovalMode(cmd)
+	if err != nil {
+		return nil, err
+	}
+	deadline := time.Now().Add(time.Duration(deadlineMin) * time.Minute)
+
+	fmt.Println("\n🔍 Simulating the swap transaction...")
+	simulation, err := c.executor.Simulate(ctx, c.opts.From, quote, c.opts.From, deadline, nil)
+	if err != nil {
+		return nil, fmt.Errorf("simulating swap: %w", err)
+	}
+	if simulation.Reverted {
+		reason := simulation.Revert
+		if reason == "" {
+			reason = "no reason given"
 		}
+		return nil, fmt.Errorf("router reverted: %s", reason)
+	}
+	fmt.Printf("  Simulated Out: %s, %d gas\n", out.Format(simulation.AmountOut()), simulation.GasUsed)
+
+	// The router pulls ERC-20 input with transferFrom; ETH is sent with the call.
+	// It is only approved once the simulation shows the swap goes through.
+	if !in.IsNative() {
+		if err := cli.EnsureApproval(ctx, c.client, c.opts, c.executor.Router(), quote, mode); err != nil {
+			return nil, err
+		}
+	}
+
+	fmt.Println("\n📤 Sending swap transaction...")
+	result, err := c.executor.Swap(ctx, c.opts, quote, c.opts.From, deadline)
+	if result != nil && result.Transaction != nil {
+		fmt.Printf("  Transaction: %s\n", result.Transaction.Hash().Hex())
 	}
+	if err != nil {
+		return nil, err
+	}
+	fmt.Printf("  Mined in block %d, gas used %d\n", result.Receipt.BlockNumber, result.Receipt.GasUsed)
 
-	return tokens
+	return result, nil
 }
 
-// executeSwap is a placeholder for the actual swap execution
-func executeSwap(client *ethclient.Client, poolAddress common.Address, tokenIn, tokenOut, amount string) {
-	// TODO: Replace with actual swap logic
-	// This would:
-	// 1. Connect to the Uniswap V2 Router contract
-	// 2. Call the appropriate swap function
-	// 3. Handle transaction signing and broadcasting
+// amountUnits converts an --amount into base units of token, reading the
+// balance of the signing account for "max" and percentages. A native balance
+// keeps back what the swap can cost in gas.
+func amountUnits(ctx context.Context, chain *chain, amount tokens.Amount, token tokens.Token) (*big.Int, error) {
+	if !amount.RelativeToBalance() {
+		return amount.BaseUnits(token.Decimals, nil)
+	}
+	if chain == nil {
+		return nil, fmt.Errorf("%s needs a balance, which simulated pools do not have", amount)
+	}
 
-	// For demo, we'll just simulate a delay
-	fmt.Println("Sending transaction...")
-	time.Sleep(2 * time.Second)
+	balance, err := tokens.Spendable(ctx, chain.client, token, chain.opts)
+	if err != nil {
+		return nil, err
+	}
 
-	// Simulate transaction success
-	fmt.Printf("Swapped %s %s for %s\n", amount, tokenIn, tokenOut)
+	return amount.BaseUnits(token.Decimals, balance)
+}
+
+// describeToken prints a resolved token with its address and decimals
+func describeToken(token tokens.Token) string {
+	if token.IsNative() {
+		return fmt.Sprintf("%s (native, %d decimals)", token.Symbol, token.Decimals)
+	}
+
+	return fmt.Sprintf("%s (%s, %d decimals)", token.Symbol, token.Address.Hex(), token.Decimals)
 }
 
 func init() {
+	// Persistent flags for all trade subcommands, matching the arbitrage command
+	TradeCmd.PersistentFlags().StringP("rpc-url", "r", "https://eth-goerli.g.alchemy.com/v2/demo", "Ethereum RPC URL")
+	TradeCmd.PersistentFlags().StringP("wallet", "w", "", "Wallet address to trade from")
+	TradeCmd.PersistentFlags().StringP("keystore-file", "k", "", "Path to keystore file")
+	TradeCmd.PersistentFlags().String("keystore-dir", "", "Keystore directory (default $TRADEBOT_KEYSTORE_DIR, then keystore_dir from the config file, then ./keystore)")
+	TradeCmd.PersistentFlags().String("signer-url", "", "External signer URL speaking Clef's account_* API (default $TRADEBOT_SIGNER_URL, then signer_url from the config file)")
+	TradeCmd.PersistentFlags().String("password-file", "", "File containing the keystore password")
+	TradeCmd.PersistentFlags().String("gas-price", "auto", "Gas price in Gwei or 'auto'")
+	TradeCmd.PersistentFlags().Uint64("gas-limit", 350000, "Gas limit for transactions")
+	TradeCmd.PersistentFlags().Bool("fake-pools", false, "Use deterministic simulated pool state instead of the RPC endpoint")
+	TradeCmd.PersistentFlags().String("router", constants.UniV2Router, "Uniswap V2 router address for executed swaps")
+	TradeCmd.PersistentFlags().String("approval", string(approval.Exact), "Router allowance to approve when too low: exact, unlimited or none")
+
 	// Add general trading flags
-	TradeCmd.Flags().StringVar(&tokenIn, "token-in", "ETH", "Input token symbol or address")
-	TradeCmd.Flags().StringVar(&tokenOut, "token-out", "", "Output token symbol or address")
-	TradeCmd.Flags().StringVar(&amount, "amount", "1.0", "Amount of input token to swap")
-	TradeCmd.Flags().Float64Var(&slippage, "slippage", 0.5, "Slippage tolerance percentage")
-	TradeCmd.Flags().UintVar(&deadlineMin, "deadline", 20, "Transaction deadline in minutes")
+	ExecuteCmd.Flags().StringVar(&tokenIn, "token-in", "ETH", "Input token symbol or address")
+	ExecuteCmd.Flags().StringVar(&tokenOut, "token-out", "", "Output token symbol or address")
+	ExecuteCmd.Flags().StringVar(&amount, "amount", "1.0", "Amount of input token to swap: whole tokens, 'max' or a percentage of the balance such as 25%")
+	ExecuteCmd.Flags().Float64Var(&slippage, "slippage", 0.5, "Slippage tolerance percentage")
+	ExecuteCmd.Flags().UintVar(&deadlineMin, "deadline", 20, "Transaction deadline in minutes")
 
 	// Add pool-specific flags
-	TradeCmd.Flags().StringVar(&targetPool, "pool", "", "Target pool for trade")
-	TradeCmd.Flags().BoolVar(&imbalanceMode, "imbalance", false, "Create imbalance for testing arbitrage")
+	ExecuteCmd.Flags().StringVar(&targetPool, "pool", "", "Target pool for trade")
+	ExecuteCmd.Flags().BoolVar(&imbalanceMode, "imbalance", false, "Create imbalance for testing arbitrage")
+	ExecuteCmd.Flags().StringVar(&targetImbalance, "target-imbalance", "", "With --imbalance, size the trade to leave the pool ratio this percentage off its target ratio (or current ratio without one), such as 3% (default: sell --amount)")
 
 	// Mark required flags for standard trading mode
 	// These are only checked when imbalanceMode is false
-	// TradeCmd.MarkFlagRequired("token-out")
-	// TradeCmd.MarkFlagRequired("amount")
+	// ExecuteCmd.MarkFlagRequired("token-out")
+	// ExecuteCmd.MarkFlagRequired("amount")
 
 	// Add this command to the parent trade command
-	TradeCmd.AddCommand(TradeCmd)
+	TradeCmd.AddCommand(ExecuteCmd)
 }
 
 /*
@@ -186,8 +565,9 @@ The newer version introduces:
 
 Pool-specific trading through targetPool
 An imbalanceMode flag specifically for creating imbalances
-Parsing pool names to identify tokens
-Random token selection for creating imbalances
+The pool's actual token pair, read through the token registry
+Random selection of the token to sell, the other one being bought
+Trades sized by --target-imbalance to leave the pool ratio a given percentage off its target
 
 The newer version is designed specifically for testing the arbitrage bot by deliberately creating imbalances in pools, while the original version was for general trading.
 